@@ -0,0 +1,140 @@
+// Package apperror defines the domain errors services return. The error
+// middleware turns them into HTTP responses; any error that is not an *Error
+// is treated as internal, logged, and reported to the client without detail.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindRateLimited
+)
+
+// Status returns the HTTP status code for errors of this kind
+func (k Kind) Status() int {
+	switch k {
+	case KindValidation:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// FieldError describes one invalid input field. Message is a format string
+// filled in with Args, so it doubles as the translation key.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Args    []any  `json:"-"`
+}
+
+// Error is a domain error with a machine-readable code. Message is safe to
+// show to clients, except for internal errors whose message is replaced. Like
+// FieldError.Message it is a format string for Args; when empty, the message
+// is made up of the field errors.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Args    []any
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	msg := e.Text(func(format string, args ...any) string {
+		return fmt.Sprintf(format, args...)
+	})
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithArgs sets the values substituted into Message
+func (e *Error) WithArgs(args ...any) *Error {
+	e.Args = args
+	return e
+}
+
+// Text renders the message with format, which lets callers translate it
+func (e *Error) Text(format func(string, ...any) string) string {
+	if e.Message != "" || len(e.Fields) == 0 {
+		return format(e.Message, e.Args...)
+	}
+	parts := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		parts[i] = f.Field + " " + format(f.Message, f.Args...)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+func RateLimited(code, message string) *Error {
+	return &Error{Kind: KindRateLimited, Code: code, Message: message}
+}
+
+// Validation reports bad input, optionally naming the offending fields
+func Validation(code, message string, fields ...FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message, Fields: fields}
+}
+
+// InvalidField reports bad input in a single field
+func InvalidField(code, field, message string) *Error {
+	return Validation(code, message, FieldError{Field: field, Message: message})
+}
+
+// Internal wraps an unexpected failure; err is logged, never sent to clients
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: "internal server error", Err: err}
+}
+
+// From returns the *Error in err's chain, or an internal error wrapping err
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
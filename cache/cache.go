@@ -0,0 +1,155 @@
+// Package cache keeps hot read results, such as story details and chapter
+// pages, out of Postgres. Values are stored as JSON so the in-process LRU and
+// Redis behave the same; callers go through Fetch, which collapses concurrent
+// misses for one key into a single load.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"web-be/metrics"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache stores encoded values under string keys. Implementations must be safe
+// for concurrent use; a failing cache is logged and bypassed, never fatal.
+type Cache interface {
+	// Get returns the value for key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+var loads singleflight.Group
+
+// inflight tracks keys with a load running and how often each was
+// invalidated since, so a load that read the database before a write cannot
+// cache its result after the write's Delete
+var inflight = struct {
+	sync.Mutex
+	keys map[string]*loadState
+}{keys: make(map[string]*loadState)}
+
+type loadState struct {
+	loaders    int
+	generation uint64
+}
+
+func startLoad(key string) (*loadState, uint64) {
+	inflight.Lock()
+	defer inflight.Unlock()
+	state, ok := inflight.keys[key]
+	if !ok {
+		state = &loadState{}
+		inflight.keys[key] = state
+	}
+	state.loaders++
+	return state, state.generation
+}
+
+func finishLoad(key string, state *loadState) {
+	inflight.Lock()
+	defer inflight.Unlock()
+	state.loaders--
+	if state.loaders == 0 {
+		delete(inflight.keys, key)
+	}
+}
+
+// invalidated reports whether key was invalidated after generation was read
+func (s *loadState) invalidated(generation uint64) bool {
+	inflight.Lock()
+	defer inflight.Unlock()
+	return s.generation != generation
+}
+
+// Fetch returns the cached value for key, or calls load and caches its result
+// for ttl. Concurrent misses for the same key share one call to load, so an
+// expiring hot key doesn't send a stampede to the database. Errors from load
+// are returned and never cached.
+//
+// Each caller gets its own decoded copy, so it may modify the result freely.
+// Hits and misses are counted under the key's prefix, e.g. "story" for
+// "story:my-slug".
+func Fetch[T any](ctx context.Context, c Cache, key string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) {
+	name, _, _ := strings.Cut(key, ":")
+
+	var value T
+	if data, ok := get(ctx, c, key); ok {
+		if err := json.Unmarshal(data, &value); err == nil {
+			metrics.CacheLookups.WithLabelValues(name, "hit").Inc()
+			return value, nil
+		}
+		slog.WarnContext(ctx, "discarding undecodable cache entry", "key", key)
+	}
+	metrics.CacheLookups.WithLabelValues(name, "miss").Inc()
+
+	data, err, _ := loads.Do(key, func() (any, error) {
+		// The load is shared, so one caller going away must not cancel it
+		ctx := context.WithoutCancel(ctx)
+		state, generation := startLoad(key)
+		defer finishLoad(key, state)
+
+		loaded, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(loaded)
+		if err != nil {
+			return nil, err
+		}
+		if state.invalidated(generation) {
+			return data, nil
+		}
+		if err := c.Set(ctx, key, data, ttl); err != nil {
+			slog.WarnContext(ctx, "cache write failed", "key", key, "error", err)
+		}
+		// An invalidation between the check and the write may have deleted
+		// the key before Set landed, so drop the entry again
+		if state.invalidated(generation) {
+			if err := c.Delete(ctx, key); err != nil {
+				slog.WarnContext(ctx, "cache invalidation failed", "keys", []string{key}, "error", err)
+			}
+		}
+		return data, nil
+	})
+	if err != nil {
+		return value, err
+	}
+
+	err = json.Unmarshal(data.([]byte), &value)
+	return value, err
+}
+
+// Invalidate removes keys after a write. Failures are logged: the entries
+// then live until their TTL runs out.
+func Invalidate(ctx context.Context, c Cache, keys ...string) {
+	// Later readers must not join a load that started before the write, and
+	// that load must not cache what it read
+	inflight.Lock()
+	for _, key := range keys {
+		loads.Forget(key)
+		if state, ok := inflight.keys[key]; ok {
+			state.generation++
+		}
+	}
+	inflight.Unlock()
+	if err := c.Delete(ctx, keys...); err != nil {
+		slog.WarnContext(ctx, "cache invalidation failed", "keys", keys, "error", err)
+	}
+}
+
+func get(ctx context.Context, c Cache, key string) ([]byte, bool) {
+	data, ok, err := c.Get(ctx, key)
+	if err != nil {
+		slog.WarnContext(ctx, "cache read failed", "key", key, "error", err)
+		return nil, false
+	}
+	return data, ok
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -18,14 +22,52 @@ type Config struct {
 	DBSSLMode      string
 	JWTSecret      string
 	JWTExpiryHours int
+	JWTKeysDir     string
+	JWTActiveKeyID string
+	JWTIssuer      string
+	JWTAudience    string
+	// JWTLegacyUntil ends the window in which HS256 tokens issued without
+	// issuer and audience are still accepted; zero means they never are
+	JWTLegacyUntil time.Time
+
+	StatsRefreshMinutes int
+	TrashRetentionDays  int
+	TrashPurgeMinutes   int
+
+	ServerReadTimeoutSeconds  int
+	ServerWriteTimeoutSeconds int
+	ServerIdleTimeoutSeconds  int
+	ShutdownTimeoutSeconds    int
+	DBConnectAttempts         int
+	DBConnectRetrySeconds     int
+
+	// MetricsAddr is the admin listener for /metrics; empty disables it
+	MetricsAddr string
+	// TracesExporter is "none", "stdout" or "otlp"
+	TracesExporter string
+
+	// TrustedProxies may set X-Forwarded-For; empty trusts no proxy
+	TrustedProxies []string
+	// RateLimitStore is "memory" or "postgres"
+	RateLimitStore    string
+	RateLimitPolicies string
+
+	// CacheStore is "memory" or "redis"
+	CacheStore string
+	// CacheSize is the number of entries the in-memory cache holds
+	CacheSize int
+	RedisURL  string
 }
 
+// DefaultJWTSecret is the placeholder used when JWT_SECRET is not set
+const DefaultJWTSecret = "default-secret"
+
 func LoadConfig() (*Config, error) {
 	_ = godotenv.Load()
 
 	jwtExpiry, _ := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "24"))
 
-	return &Config{
+	cfg := &Config{
 		Port:           getEnv("PORT", "8080"),
 		GinMode:        getEnv("GIN_MODE", "debug"),
 		DBHost:         getEnv("DB_HOST", "localhost"),
@@ -34,9 +76,58 @@ func LoadConfig() (*Config, error) {
 		DBPassword:     getEnv("DB_PASSWORD", ""),
 		DBName:         getEnv("DB_NAME", "story_reader"),
 		DBSSLMode:      getEnv("DB_SSLMODE", "disable"),
-		JWTSecret:      getEnv("JWT_SECRET", "default-secret"),
+		JWTSecret:      getEnv("JWT_SECRET", DefaultJWTSecret),
 		JWTExpiryHours: jwtExpiry,
-	}, nil
+		JWTKeysDir:     getEnv("JWT_KEYS_DIR", ""),
+		JWTActiveKeyID: getEnv("JWT_ACTIVE_KID", ""),
+		JWTIssuer:      getEnv("JWT_ISSUER", "web-be"),
+		JWTAudience:    getEnv("JWT_AUDIENCE", "web-be-api"),
+
+		StatsRefreshMinutes: getPositiveInt("STATS_REFRESH_MINUTES", 15),
+		TrashRetentionDays:  getPositiveInt("TRASH_RETENTION_DAYS", 30),
+		TrashPurgeMinutes:   getPositiveInt("TRASH_PURGE_MINUTES", 60),
+
+		ServerReadTimeoutSeconds:  getPositiveInt("SERVER_READ_TIMEOUT_SECONDS", 15),
+		ServerWriteTimeoutSeconds: getPositiveInt("SERVER_WRITE_TIMEOUT_SECONDS", 60),
+		ServerIdleTimeoutSeconds:  getPositiveInt("SERVER_IDLE_TIMEOUT_SECONDS", 120),
+		ShutdownTimeoutSeconds:    getPositiveInt("SHUTDOWN_TIMEOUT_SECONDS", 30),
+		DBConnectAttempts:         getPositiveInt("DB_CONNECT_ATTEMPTS", 10),
+		DBConnectRetrySeconds:     getPositiveInt("DB_CONNECT_RETRY_SECONDS", 2),
+
+		MetricsAddr:    getEnv("METRICS_ADDR", ":9090"),
+		TracesExporter: getEnv("OTEL_TRACES_EXPORTER", "none"),
+
+		TrustedProxies:    getList("TRUSTED_PROXIES"),
+		RateLimitStore:    getEnv("RATE_LIMIT_STORE", "memory"),
+		RateLimitPolicies: getEnv("RATE_LIMIT_POLICIES", "login=10/1m,register=5/1h,search=60/1m,chapter=300/1m"),
+
+		CacheStore: getEnv("CACHE_STORE", "memory"),
+		CacheSize:  getPositiveInt("CACHE_SIZE", 10000),
+		RedisURL:   getEnv("REDIS_URL", "redis://localhost:6379/0"),
+	}
+
+	if value := getEnv("JWT_LEGACY_UNTIL", ""); value != "" {
+		until, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_LEGACY_UNTIL: %w", err)
+		}
+		cfg.JWTLegacyUntil = until
+	}
+
+	if cfg.GinMode == "release" && cfg.JWTKeysDir == "" && cfg.JWTSecret == DefaultJWTSecret {
+		return nil, errors.New("refusing to start in release mode with the default JWT secret: set JWT_SECRET or JWT_KEYS_DIR")
+	}
+
+	return cfg, nil
+}
+
+// LegacyJWTSecret returns the HS256 secret to keep accepting, or "" when
+// asymmetric keys are configured and the secret is only the placeholder
+func (c *Config) LegacyJWTSecret() string {
+	if c.JWTKeysDir != "" && c.JWTSecret == DefaultJWTSecret {
+		return ""
+	}
+	return c.JWTSecret
 }
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
@@ -44,3 +135,24 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getPositiveInt reads an integer setting, using defaultValue when it is
+// missing, malformed or not positive
+func getPositiveInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil || value < 1 {
+		return defaultValue
+	}
+	return value
+}
+
+// getList reads a comma-separated setting, dropping empty entries
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+
+	"web-be/metrics"
+	"web-be/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// repositoryPrefix is how repository functions appear in stack frames
+const repositoryPrefix = "web-be/repository."
+
+// instrumentedConnector wraps the Postgres connector so every query is timed,
+// traced and attributed to the repository method that issued it, without each
+// repository having to instrument itself
+type instrumentedConnector struct {
+	driver.Connector
+}
+
+func (c instrumentedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConn{Conn: conn}, nil
+}
+
+// instrumentedConn times queries and execs and passes every optional driver
+// interface through to the wrapped connection
+type instrumentedConn struct {
+	driver.Conn
+}
+
+func (c *instrumentedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, done := startQuery(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	done(err)
+	return rows, err
+}
+
+func (c *instrumentedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, done := startQuery(ctx, query)
+	result, err := execer.ExecContext(ctx, query, args)
+	done(err)
+	return result, err
+}
+
+func (c *instrumentedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *instrumentedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *instrumentedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *instrumentedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *instrumentedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// startQuery times a query and, inside a traced request, opens a client span
+// named after the repository method; the returned func ends both
+func startQuery(ctx context.Context, query string) (context.Context, func(error)) {
+	start := time.Now()
+	repository, method := queryCaller()
+
+	var span trace.Span
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		name := repository + "." + method
+		if repository == "other" {
+			name = sqlOperation(query)
+		}
+		ctx, span = tracing.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system.name", "postgresql"),
+				attribute.String("db.operation.name", sqlOperation(query)),
+				attribute.String("db.query.text", sanitizeQuery(query)),
+			),
+		)
+	}
+
+	return ctx, func(err error) {
+		metrics.ObserveQuery(repository, method, time.Since(start))
+		if span == nil {
+			return
+		}
+		if err != nil && !errors.Is(err, driver.ErrSkip) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "query failed")
+		}
+		span.End()
+	}
+}
+
+var (
+	stringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	numericLiteral = regexp.MustCompile(`\$?\b\d+(?:\.\d+)?\b`)
+	whitespace     = regexp.MustCompile(`\s+`)
+)
+
+// sanitizeQuery replaces literals with ? so values written into a statement
+// never reach the trace backend; $n placeholders are kept
+func sanitizeQuery(query string) string {
+	query = stringLiteral.ReplaceAllString(query, "?")
+	query = numericLiteral.ReplaceAllStringFunc(query, func(m string) string {
+		if strings.HasPrefix(m, "$") {
+			return m
+		}
+		return "?"
+	})
+	return strings.TrimSpace(whitespace.ReplaceAllString(query, " "))
+}
+
+// sqlOperation returns the statement's leading keyword, such as SELECT
+func sqlOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
+
+// queryCaller finds the repository method on the stack, returning for example
+// ("reading_list", "GetItems"). Queries from outside the repositories, such as
+// migrations, are reported as ("other", "other").
+func queryCaller() (string, string) {
+	var pcs [48]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		// Methods look like "(*StoryRepository).GetBySlug", possibly followed
+		// by ".func1" for closures; package-level helpers are skipped so the
+		// method that called them is found
+		if name, ok := strings.CutPrefix(frame.Function, repositoryPrefix+"(*"); ok {
+			receiver, rest, _ := strings.Cut(name, ").")
+			method, _, _ := strings.Cut(rest, ".")
+			return snakeCase(strings.TrimSuffix(receiver, "Repository")), method
+		}
+		if !more {
+			return "other", "other"
+		}
+	}
+}
+
+// snakeCase turns a type name such as "APIToken" or "ReadingList" into
+// "api_token" or "reading_list"
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"fmt"
 	"io/fs"
 	"os"
@@ -11,6 +12,23 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// schema_migrations records which .up.sql files have been applied, so each
+// runs exactly once. Databases created before tracking existed re-run every
+// migration once, which is safe because they are all idempotent.
+const createMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)
+`
+
+// MigrationState compares the migration files on disk with the database
+type MigrationState struct {
+	Applied int      `json:"applied"`
+	Pending []string `json:"pending"`
+	Latest  string   `json:"latest,omitempty"`
+}
+
 func RunMigrations(db *sqlx.DB, migrationsDir string) error {
 	absDir, err := filepath.Abs(migrationsDir)
 	if err != nil {
@@ -19,9 +37,68 @@ func RunMigrations(db *sqlx.DB, migrationsDir string) error {
 
 	fmt.Printf("Running migrations from: %s\n", absDir)
 
+	files, err := migrationFiles(absDir)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(createMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	applied, err := appliedVersions(context.Background(), db)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		version := migrationVersion(file)
+		if applied[version] {
+			continue
+		}
+
+		fmt.Printf("Running migration: %s\n", filepath.Base(file))
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", file, err)
+		}
+
+		if err := applyMigration(db, version, string(content)); err != nil {
+			return fmt.Errorf("migration failed (%s): %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// Migrations reports how many migrations are applied and which are pending
+func Migrations(ctx context.Context, db *sqlx.DB, migrationsDir string) (*MigrationState, error) {
+	files, err := migrationFiles(migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &MigrationState{Pending: []string{}}
+	for _, file := range files {
+		version := migrationVersion(file)
+		if applied[version] {
+			state.Applied++
+			state.Latest = version
+		} else {
+			state.Pending = append(state.Pending, version)
+		}
+	}
+	return state, nil
+}
+
+func migrationFiles(dir string) ([]string, error) {
 	var files []string
 
-	err = filepath.WalkDir(absDir, func(path string, d fs.DirEntry, err error) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -33,23 +110,43 @@ func RunMigrations(db *sqlx.DB, migrationsDir string) error {
 		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("failed to read migrations dir: %w", err)
+		return nil, fmt.Errorf("failed to read migrations dir: %w", err)
 	}
 
 	sort.Strings(files)
+	return files, nil
+}
 
-	for _, file := range files {
-		fmt.Printf("Running migration: %s\n", filepath.Base(file))
+func migrationVersion(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), ".up.sql")
+}
 
-		content, err := os.ReadFile(file)
-		if err != nil {
-			return fmt.Errorf("failed to read migration %s: %w", file, err)
-		}
+func appliedVersions(ctx context.Context, db *sqlx.DB) (map[string]bool, error) {
+	var versions []string
+	if err := db.SelectContext(ctx, &versions, `SELECT version FROM schema_migrations`); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
 
-		if _, err := db.Exec(string(content)); err != nil {
-			return fmt.Errorf("migration failed (%s): %w", file, err)
-		}
+	applied := make(map[string]bool, len(versions))
+	for _, v := range versions {
+		applied[v] = true
 	}
+	return applied, nil
+}
 
-	return nil
+// applyMigration runs one migration and records it in the same transaction
+func applyMigration(db *sqlx.DB, version, content string) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(content); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
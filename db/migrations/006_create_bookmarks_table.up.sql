@@ -6,5 +6,5 @@ CREATE TABLE IF NOT EXISTS bookmarks (
     UNIQUE(user_id, story_id)
 );
 
-CREATE INDEX idx_bookmarks_user_id ON bookmarks(user_id);
-CREATE INDEX idx_bookmarks_story_id ON bookmarks(story_id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_user_id ON bookmarks(user_id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_story_id ON bookmarks(story_id);
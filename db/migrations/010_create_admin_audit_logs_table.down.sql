@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_admin_audit_logs_created_at;
+DROP INDEX IF EXISTS idx_admin_audit_logs_target;
+DROP INDEX IF EXISTS idx_admin_audit_logs_actor;
+DROP TABLE IF EXISTS admin_audit_logs;
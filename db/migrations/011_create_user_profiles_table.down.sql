@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_user_follows_followee;
+DROP TABLE IF EXISTS user_follows;
+DROP TABLE IF EXISTS user_profiles;
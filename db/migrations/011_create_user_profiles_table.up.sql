@@ -0,0 +1,22 @@
+-- Create user_profiles table (public author page details and privacy)
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    bio TEXT,
+    social_links JSONB NOT NULL DEFAULT '{}',
+    show_social_links BOOLEAN NOT NULL DEFAULT true,
+    show_join_date BOOLEAN NOT NULL DEFAULT true,
+    show_stats BOOLEAN NOT NULL DEFAULT true,
+    show_followers BOOLEAN NOT NULL DEFAULT true,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create user_follows table (reader follows author)
+CREATE TABLE IF NOT EXISTS user_follows (
+    follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_follows_followee ON user_follows(followee_id);
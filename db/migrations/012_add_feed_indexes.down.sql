@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_chapters_story_published;
+DROP INDEX IF EXISTS idx_stories_author_created;
+DROP INDEX IF EXISTS idx_user_follows_follower;
@@ -0,0 +1,4 @@
+-- Indexes backing the fan-out-on-read activity feed
+CREATE INDEX IF NOT EXISTS idx_user_follows_follower ON user_follows(follower_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_stories_author_created ON stories(author_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_chapters_story_published ON chapters(story_id, published_at DESC) WHERE is_published = true;
@@ -0,0 +1,15 @@
+-- Put stories back as bookmarks before dropping the lists
+INSERT INTO bookmarks (user_id, story_id, created_at)
+SELECT rl.user_id, i.story_id, MIN(i.added_at)
+FROM reading_list_items i
+INNER JOIN reading_lists rl ON i.list_id = rl.id
+GROUP BY rl.user_id, i.story_id
+ON CONFLICT (user_id, story_id) DO NOTHING;
+
+DROP INDEX IF EXISTS idx_reading_list_follows_list;
+DROP TABLE IF EXISTS reading_list_follows;
+DROP INDEX IF EXISTS idx_reading_list_items_story;
+DROP TABLE IF EXISTS reading_list_items;
+DROP INDEX IF EXISTS idx_reading_lists_system;
+DROP INDEX IF EXISTS idx_reading_lists_user;
+DROP TABLE IF EXISTS reading_lists;
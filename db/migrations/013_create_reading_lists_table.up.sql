@@ -0,0 +1,52 @@
+-- Create reading_lists table (named shelves per user)
+CREATE TABLE IF NOT EXISTS reading_lists (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    share_code VARCHAR(16) UNIQUE NOT NULL,
+    is_public BOOLEAN NOT NULL DEFAULT false,
+    system_key VARCHAR(20),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_lists_user ON reading_lists(user_id);
+-- Each user has at most one of each built-in list (reading, plan_to_read, completed)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reading_lists_system ON reading_lists(user_id, system_key) WHERE system_key IS NOT NULL;
+
+-- Create reading_list_items table (ordered stories in a list)
+CREATE TABLE IF NOT EXISTS reading_list_items (
+    id SERIAL PRIMARY KEY,
+    list_id INTEGER NOT NULL REFERENCES reading_lists(id) ON DELETE CASCADE,
+    story_id INTEGER NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(list_id, story_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_list_items_story ON reading_list_items(story_id);
+
+-- Create reading_list_follows table (users following someone else's public list)
+CREATE TABLE IF NOT EXISTS reading_list_follows (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    list_id INTEGER NOT NULL REFERENCES reading_lists(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, list_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_list_follows_list ON reading_list_follows(list_id);
+
+-- Move existing bookmarks into each user's "Đang đọc" list
+INSERT INTO reading_lists (user_id, name, share_code, system_key)
+SELECT DISTINCT b.user_id, 'Đang đọc', substr(md5(random()::text || b.user_id::text), 1, 12), 'reading'
+FROM bookmarks b
+ON CONFLICT (user_id, system_key) WHERE system_key IS NOT NULL DO NOTHING;
+
+INSERT INTO reading_list_items (list_id, story_id, position, added_at)
+SELECT rl.id, b.story_id, ROW_NUMBER() OVER (PARTITION BY b.user_id ORDER BY b.created_at), b.created_at
+FROM bookmarks b
+INNER JOIN reading_lists rl ON rl.user_id = b.user_id AND rl.system_key = 'reading'
+ON CONFLICT (list_id, story_id) DO NOTHING;
+
+DELETE FROM bookmarks;
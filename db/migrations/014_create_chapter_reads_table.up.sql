@@ -0,0 +1,23 @@
+-- Create chapter_reads table (per-chapter reading progress)
+CREATE TABLE IF NOT EXISTS chapter_reads (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    chapter_id INTEGER NOT NULL REFERENCES chapters(id) ON DELETE CASCADE,
+    story_id INTEGER NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    paragraph_index INTEGER NOT NULL DEFAULT 0,
+    scroll_offset INTEGER NOT NULL DEFAULT 0,
+    progress_percent SMALLINT NOT NULL DEFAULT 0 CHECK (progress_percent BETWEEN 0 AND 100),
+    first_read_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_read_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    PRIMARY KEY (user_id, chapter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chapter_reads_user_story ON chapter_reads(user_id, story_id);
+
+-- Chapters up to the last one recorded in reading_history count as read
+INSERT INTO chapter_reads (user_id, chapter_id, story_id, progress_percent, first_read_at, last_read_at, completed_at)
+SELECT rh.user_id, c.id, rh.story_id, 100, rh.last_read_at, rh.last_read_at, rh.last_read_at
+FROM reading_history rh
+INNER JOIN chapters last ON rh.last_chapter_id = last.id
+INNER JOIN chapters c ON c.story_id = rh.story_id AND c.chapter_number <= last.chapter_number
+ON CONFLICT (user_id, chapter_id) DO NOTHING;
@@ -0,0 +1,20 @@
+-- Create reading_events table (one row per chapter a user reads on a given day)
+CREATE TABLE IF NOT EXISTS reading_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    story_id INTEGER NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    chapter_id INTEGER NOT NULL REFERENCES chapters(id) ON DELETE CASCADE,
+    word_count INTEGER NOT NULL DEFAULT 0,
+    read_on DATE NOT NULL DEFAULT CURRENT_DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(user_id, chapter_id, read_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reading_events_user_day ON reading_events(user_id, read_on);
+
+-- Seed events from chapter progress recorded before events existed
+INSERT INTO reading_events (user_id, story_id, chapter_id, word_count, read_on, created_at)
+SELECT cr.user_id, cr.story_id, cr.chapter_id, c.word_count, cr.last_read_at::date, cr.last_read_at
+FROM chapter_reads cr
+INNER JOIN chapters c ON cr.chapter_id = c.id
+ON CONFLICT (user_id, chapter_id, read_on) DO NOTHING;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_user_follows_followee_created;
+DROP INDEX IF EXISTS idx_reading_events_chapter_day;
+DROP INDEX IF EXISTS idx_reading_events_story_day;
+DROP TABLE IF EXISTS chapter_views_daily;
+DROP TABLE IF EXISTS story_views_daily;
@@ -0,0 +1,20 @@
+-- Daily view counters backing author analytics time series
+CREATE TABLE IF NOT EXISTS story_views_daily (
+    story_id INTEGER NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    view_date DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (story_id, view_date)
+);
+
+CREATE TABLE IF NOT EXISTS chapter_views_daily (
+    chapter_id INTEGER NOT NULL REFERENCES chapters(id) ON DELETE CASCADE,
+    story_id INTEGER NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    view_date DATE NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (chapter_id, view_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chapter_views_daily_story ON chapter_views_daily(story_id, view_date);
+CREATE INDEX IF NOT EXISTS idx_reading_events_story_day ON reading_events(story_id, read_on);
+CREATE INDEX IF NOT EXISTS idx_reading_events_chapter_day ON reading_events(chapter_id, read_on);
+CREATE INDEX IF NOT EXISTS idx_user_follows_followee_created ON user_follows(followee_id, created_at);
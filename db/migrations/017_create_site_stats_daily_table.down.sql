@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_story_views_daily_day;
+DROP INDEX IF EXISTS idx_reading_events_day;
+DROP INDEX IF EXISTS idx_chapters_created_at;
+DROP INDEX IF EXISTS idx_stories_created_at;
+DROP TABLE IF EXISTS site_stats_daily;
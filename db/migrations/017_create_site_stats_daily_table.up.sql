@@ -0,0 +1,18 @@
+-- Daily site-wide rollup for the admin dashboard, refreshed by a background job
+CREATE TABLE IF NOT EXISTS site_stats_daily (
+    day DATE PRIMARY KEY,
+    signups INTEGER NOT NULL DEFAULT 0,
+    active_users INTEGER NOT NULL DEFAULT 0,
+    monthly_active_users INTEGER NOT NULL DEFAULT 0,
+    new_stories INTEGER NOT NULL DEFAULT 0,
+    new_chapters INTEGER NOT NULL DEFAULT 0,
+    chapters_read INTEGER NOT NULL DEFAULT 0,
+    views BIGINT NOT NULL DEFAULT 0,
+    refreshed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Indexes for recomputing a day (users.created_at is indexed by 009)
+CREATE INDEX IF NOT EXISTS idx_stories_created_at ON stories(created_at);
+CREATE INDEX IF NOT EXISTS idx_chapters_created_at ON chapters(created_at);
+CREATE INDEX IF NOT EXISTS idx_reading_events_day ON reading_events(read_on);
+CREATE INDEX IF NOT EXISTS idx_story_views_daily_day ON story_views_daily(view_date);
@@ -0,0 +1,23 @@
+-- Create tags table (free-form, normalized labels authors attach to stories).
+-- A tag with canonical_id set is a synonym of that tag.
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    slug VARCHAR(60) UNIQUE NOT NULL,
+    canonical_id INTEGER REFERENCES tags(id) ON DELETE CASCADE,
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (canonical_id IS NULL OR canonical_id <> id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tags_slug_prefix ON tags(slug varchar_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_tags_canonical ON tags(canonical_id);
+
+-- Create story_tags junction table (only canonical tags are stored)
+CREATE TABLE IF NOT EXISTS story_tags (
+    story_id INTEGER REFERENCES stories(id) ON DELETE CASCADE,
+    tag_id INTEGER REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (story_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_story_tags_tag ON story_tags(tag_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS category_slug_redirects;
+DROP INDEX IF EXISTS idx_categories_parent;
+ALTER TABLE categories DROP COLUMN IF EXISTS display_order;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
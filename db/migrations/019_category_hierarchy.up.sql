@@ -0,0 +1,14 @@
+-- Two-level category tree and admin-controlled ordering
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS display_order INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories(parent_id);
+
+-- Old slugs of renamed or merged categories, so existing links keep working
+CREATE TABLE IF NOT EXISTS category_slug_redirects (
+    old_slug VARCHAR(100) PRIMARY KEY,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_category_slug_redirects_category ON category_slug_redirects(category_id);
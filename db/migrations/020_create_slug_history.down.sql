@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS chapter_slug_history;
+DROP TABLE IF EXISTS story_slug_history;
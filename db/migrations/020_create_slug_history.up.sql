@@ -0,0 +1,20 @@
+-- Previous story slugs; lookups by an old slug redirect to the current one.
+-- A slug here is never the current slug of any story.
+CREATE TABLE IF NOT EXISTS story_slug_history (
+    old_slug VARCHAR(255) PRIMARY KEY,
+    story_id INTEGER NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_story_slug_history_story ON story_slug_history(story_id);
+
+-- Previous chapter slugs, scoped to their story
+CREATE TABLE IF NOT EXISTS chapter_slug_history (
+    story_id INTEGER NOT NULL REFERENCES stories(id) ON DELETE CASCADE,
+    old_slug VARCHAR(255) NOT NULL,
+    chapter_id INTEGER NOT NULL REFERENCES chapters(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (story_id, old_slug)
+);
+
+CREATE INDEX IF NOT EXISTS idx_chapter_slug_history_chapter ON chapter_slug_history(chapter_id);
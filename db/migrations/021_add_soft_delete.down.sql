@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_chapters_deleted_at;
+DROP INDEX IF EXISTS idx_stories_deleted_at;
+ALTER TABLE chapters DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE stories DROP COLUMN IF EXISTS deleted_at;
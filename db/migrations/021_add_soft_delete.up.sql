@@ -0,0 +1,6 @@
+-- Soft delete: trashed stories and chapters keep their rows until restored or purged
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE chapters ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_stories_deleted_at ON stories(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_chapters_deleted_at ON chapters(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Preferred language for API messages; NULL falls back to Accept-Language
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(5);
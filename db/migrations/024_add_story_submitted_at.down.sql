@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stories_pending_review;
+ALTER TABLE stories DROP COLUMN IF EXISTS submitted_at;
@@ -0,0 +1,6 @@
+-- Set when an author submits an unpublished story for an admin to publish;
+-- cleared when it is published
+ALTER TABLE stories ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_stories_pending_review ON stories(submitted_at)
+    WHERE submitted_at IS NOT NULL AND is_published = false AND deleted_at IS NULL;
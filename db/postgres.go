@@ -1,23 +1,49 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"web-be/config"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
+// maxRetryDelay caps the doubling wait between connection attempts
+const maxRetryDelay = 30 * time.Second
+
+// NewPostgresDB connects to Postgres, retrying with a doubling delay so the
+// API can start alongside a database that is still coming up
 func NewPostgresDB(cfg *config.Config) (*sqlx.DB, error) {
 	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBSSLMode,
 	)
 
-	db, err := sqlx.Connect("postgres", dsn)
+	connector, err := pq.NewConnector(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("invalid database settings: %w", err)
+	}
+	db := sqlx.NewDb(sql.OpenDB(instrumentedConnector{Connector: connector}), "postgres")
+
+	delay := time.Duration(cfg.DBConnectRetrySeconds) * time.Second
+	for attempt := 1; ; attempt++ {
+		err = db.PingContext(context.Background())
+		if err == nil {
+			break
+		}
+		if attempt >= cfg.DBConnectAttempts {
+			db.Close()
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		}
+
+		slog.Warn("database not reachable, retrying", "attempt", attempt, "retry_in", delay.String(), "error", err)
+		time.Sleep(delay)
+		delay = min(delay*2, maxRetryDelay)
 	}
 
 	// Connection pool settings
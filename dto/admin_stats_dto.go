@@ -0,0 +1,36 @@
+package dto
+
+import "web-be/models"
+
+type AdminStatsDayResponse struct {
+	Date               string `json:"date"`
+	Signups            int    `json:"signups"`
+	ActiveUsers        int    `json:"active_users"`
+	MonthlyActiveUsers int    `json:"monthly_active_users"`
+	NewStories         int    `json:"new_stories"`
+	NewChapters        int    `json:"new_chapters"`
+	ChaptersRead       int    `json:"chapters_read"`
+	Views              int64  `json:"views"`
+}
+
+type AdminStatsResponse struct {
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	RefreshedAt *string `json:"refreshed_at,omitempty"`
+
+	Totals models.SiteTotals `json:"totals"`
+
+	// Latest day in the rollup
+	DailyActiveUsers   int `json:"daily_active_users"`
+	MonthlyActiveUsers int `json:"monthly_active_users"`
+
+	// Sums over the requested range
+	Signups      int   `json:"signups"`
+	NewStories   int   `json:"new_stories"`
+	NewChapters  int   `json:"new_chapters"`
+	ChaptersRead int   `json:"chapters_read"`
+	Views        int64 `json:"views"`
+
+	Daily      []AdminStatsDayResponse `json:"daily"`
+	TopStories []models.TopStory       `json:"top_stories"`
+}
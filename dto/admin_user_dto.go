@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type AdminUserFilterRequest struct {
+	PaginationRequest
+	Query       string    `form:"q"`
+	Role        string    `form:"role" binding:"omitempty,oneof=user author admin"`
+	Status      string    `form:"status" binding:"omitempty,oneof=active suspended banned"`
+	CreatedFrom time.Time `form:"created_from" time_format:"2006-01-02"`
+	CreatedTo   time.Time `form:"created_to" time_format:"2006-01-02"`
+}
+
+type AdminUserResponse struct {
+	ID                int        `json:"id"`
+	Username          string     `json:"username"`
+	Email             string     `json:"email"`
+	FullName          *string    `json:"full_name,omitempty"`
+	AvatarURL         *string    `json:"avatar_url,omitempty"`
+	Role              string     `json:"role"`
+	Status            string     `json:"status"`
+	SuspendedUntil    *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason  *string    `json:"suspension_reason,omitempty"`
+	BanReason         *string    `json:"ban_reason,omitempty"`
+	MustResetPassword bool       `json:"must_reset_password"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type SuspendUserRequest struct {
+	Reason string    `json:"reason" binding:"required,min=1,max=500"`
+	Until  time.Time `json:"until" binding:"required"`
+}
+
+type BanUserRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user author admin"`
+}
+
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+type ImpersonationResponse struct {
+	Token     string       `json:"token"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	ReadOnly  bool         `json:"read_only"`
+	User      UserResponse `json:"user"`
+}
@@ -0,0 +1,67 @@
+package dto
+
+type ExportAnalyticsRequest struct {
+	DateRangeRequest
+	Report string `form:"report" binding:"omitempty,oneof=daily chapters"`
+}
+
+type StoryAnalyticsDayResponse struct {
+	Date          string `json:"date"`
+	Views         int64  `json:"views"`
+	UniqueReaders int    `json:"unique_readers"`
+	NewBookmarks  int    `json:"new_bookmarks"`
+	NewFollowers  int    `json:"new_followers"`
+}
+
+type ChapterAnalyticsResponse struct {
+	ChapterID        int    `json:"chapter_id"`
+	ChapterNumber    int    `json:"chapter_number"`
+	Title            string `json:"title"`
+	Views            int64  `json:"views"`
+	UniqueReaders    int    `json:"unique_readers"`
+	Completions      int    `json:"completions"`
+	ContinuedReaders int    `json:"continued_readers"`
+	// Share of this chapter's readers who did not open the next chapter;
+	// omitted for the last chapter and chapters without readers
+	DropOffPercent *float64 `json:"drop_off_percent,omitempty"`
+}
+
+type StoryAnalyticsResponse struct {
+	StoryID    int    `json:"story_id"`
+	StoryTitle string `json:"story_title"`
+	StorySlug  string `json:"story_slug"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+
+	// Lifetime figures
+	TotalViews    int64   `json:"total_views"`
+	TotalChapters int     `json:"total_chapters"`
+	Rating        float64 `json:"rating"`
+	Bookmarks     int64   `json:"bookmarks"`
+	Followers     int64   `json:"followers"`
+
+	// Figures for the requested range
+	Views         int64 `json:"views"`
+	UniqueReaders int   `json:"unique_readers"`
+	NewBookmarks  int   `json:"new_bookmarks"`
+	NewFollowers  int   `json:"new_followers"`
+
+	Daily    []StoryAnalyticsDayResponse `json:"daily"`
+	Chapters []ChapterAnalyticsResponse  `json:"chapters"`
+}
+
+type ChapterAnalyticsDayResponse struct {
+	Date          string `json:"date"`
+	Views         int64  `json:"views"`
+	UniqueReaders int    `json:"unique_readers"`
+	Completions   int    `json:"completions"`
+}
+
+type ChapterDailyAnalyticsResponse struct {
+	ChapterID     int                           `json:"chapter_id"`
+	ChapterNumber int                           `json:"chapter_number"`
+	Title         string                        `json:"title"`
+	From          string                        `json:"from"`
+	To            string                        `json:"to"`
+	Daily         []ChapterAnalyticsDayResponse `json:"daily"`
+}
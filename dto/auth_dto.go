@@ -15,6 +15,8 @@ type LoginRequest struct {
 type AuthResponse struct {
 	Token string       `json:"token"`
 	User  UserResponse `json:"user"`
+	// MustResetPassword is set when an admin forced a password reset
+	MustResetPassword bool `json:"must_reset_password,omitempty"`
 }
 
 type UserResponse struct {
@@ -24,9 +26,18 @@ type UserResponse struct {
 	FullName  *string `json:"full_name,omitempty"`
 	AvatarURL *string `json:"avatar_url,omitempty"`
 	Role      string  `json:"role"`
+	Language  *string `json:"language,omitempty"`
 }
 
 type UpdateProfileRequest struct {
 	FullName  *string `json:"full_name"`
 	AvatarURL *string `json:"avatar_url"`
+	// Language is "en", "vi" or "" to clear it; it applies to tokens issued
+	// after the change
+	Language *string `json:"language" binding:"omitempty,oneof=en vi"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
 }
@@ -10,6 +10,8 @@ type UpdateChapterRequest struct {
 	Title       *string `json:"title" binding:"omitempty,min=1,max=255"`
 	Content     *string `json:"content"`
 	IsPublished *bool   `json:"is_published"`
+	// Keep the current slug when the title changes
+	KeepSlug bool `json:"keep_slug"`
 }
 
 type ChapterResponse struct {
@@ -29,6 +31,9 @@ type ChapterResponse struct {
 	// Navigation
 	PrevChapter *ChapterNavItem `json:"prev_chapter,omitempty"`
 	NextChapter *ChapterNavItem `json:"next_chapter,omitempty"`
+
+	// Saved position, only for authenticated readers
+	Progress *ReadingProgressResponse `json:"progress,omitempty"`
 }
 
 type ChapterListResponse struct {
@@ -42,6 +47,10 @@ type ChapterListResponse struct {
 	IsPublished   bool    `json:"is_published"`
 	PublishedAt   *string `json:"published_at,omitempty"`
 	CreatedAt     string  `json:"created_at"`
+
+	// Read markers, only for authenticated readers
+	IsRead          *bool `json:"is_read,omitempty"`
+	ProgressPercent *int  `json:"progress_percent,omitempty"`
 }
 
 type ChapterNavItem struct {
@@ -49,3 +58,18 @@ type ChapterNavItem struct {
 	Title         string `json:"title"`
 	Slug          string `json:"slug"`
 }
+
+type UpdateReadingProgressRequest struct {
+	ParagraphIndex  int `json:"paragraph_index" binding:"min=0"`
+	ScrollOffset    int `json:"scroll_offset" binding:"min=0"`
+	ProgressPercent int `json:"progress_percent" binding:"min=0,max=100"`
+}
+
+type ReadingProgressResponse struct {
+	ParagraphIndex  int     `json:"paragraph_index"`
+	ScrollOffset    int     `json:"scroll_offset"`
+	ProgressPercent int     `json:"progress_percent"`
+	IsRead          bool    `json:"is_read"`
+	LastReadAt      string  `json:"last_read_at"`
+	CompletedAt     *string `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"web-be/apperror"
+)
+
+// DateLayout is the format of dates in query strings and responses
+const DateLayout = "2006-01-02"
+
+type DateRangeRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// Resolve returns the inclusive range as dates. A missing end defaults to
+// today and a missing start to defaultDays before the end.
+func (r *DateRangeRequest) Resolve(defaultDays, maxDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if r.To != "" {
+		to, _ = time.Parse(DateLayout, r.To)
+	}
+	from := to.AddDate(0, 0, -(defaultDays - 1))
+	if r.From != "" {
+		from, _ = time.Parse(DateLayout, r.From)
+	}
+
+	if from.After(to) {
+		return from, to, apperror.InvalidField("invalid_date_range", "from", "from must not be after to")
+	}
+	if !from.AddDate(0, 0, maxDays).After(to) {
+		const message = "date range must not exceed %d days"
+		return from, to, apperror.Validation("invalid_date_range", message,
+			apperror.FieldError{Field: "to", Message: message, Args: []any{maxDays}}).WithArgs(maxDays)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "web-be/models"
+
+type FollowStatusResponse struct {
+	IsFollowing   bool  `json:"is_following"`
+	FollowerCount int64 `json:"follower_count"`
+}
+
+type FeedRequest struct {
+	Cursor string `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+type FeedResponse struct {
+	Items      []models.FeedItem `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
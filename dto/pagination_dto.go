@@ -40,4 +40,9 @@ func (p *PaginationRequest) Normalize() {
 type SearchRequest struct {
 	PaginationRequest
 	Query string `form:"q" binding:"required,min=1"`
+	Tags  string `form:"tags"`
+}
+
+func (r *SearchRequest) TagSlugs() []string {
+	return splitTagSlugs(r.Tags)
 }
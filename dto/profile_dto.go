@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"time"
+
+	"web-be/models"
+)
+
+type UpdatePublicProfileRequest struct {
+	Bio             *string           `json:"bio" binding:"omitempty,max=2000"`
+	SocialLinks     map[string]string `json:"social_links" binding:"omitempty,max=10,dive,keys,oneof=website facebook twitter instagram youtube tiktok,endkeys,url"`
+	ShowSocialLinks *bool             `json:"show_social_links"`
+	ShowJoinDate    *bool             `json:"show_join_date"`
+	ShowStats       *bool             `json:"show_stats"`
+	ShowFollowers   *bool             `json:"show_followers"`
+}
+
+// PublicProfileResponse is what readers see on an author page; optional
+// sections are omitted when the author's privacy settings hide them
+type PublicProfileResponse struct {
+	Username      string              `json:"username"`
+	FullName      *string             `json:"full_name,omitempty"`
+	AvatarURL     *string             `json:"avatar_url,omitempty"`
+	Bio           *string             `json:"bio,omitempty"`
+	SocialLinks   models.SocialLinks  `json:"social_links,omitempty"`
+	JoinedAt      *time.Time          `json:"joined_at,omitempty"`
+	FollowerCount *int64              `json:"follower_count,omitempty"`
+	Stats         *models.AuthorStats `json:"stats,omitempty"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "web-be/models"
+
+type CreateReadingListRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=100"`
+	Description *string `json:"description"`
+	IsPublic    bool    `json:"is_public"`
+}
+
+type UpdateReadingListRequest struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description"`
+	IsPublic    *bool   `json:"is_public"`
+}
+
+type MyReadingListsRequest struct {
+	StoryID int `form:"story_id" binding:"omitempty,min=1"`
+}
+
+type ReadingListItemRequest struct {
+	StoryID int `json:"story_id" binding:"required,min=1"`
+}
+
+type ReorderReadingListRequest struct {
+	StoryIDs []int `json:"story_ids" binding:"required,min=1,dive,min=1"`
+}
+
+type ReadingListResponse struct {
+	models.ReadingListSummary
+	IsOwner     bool `json:"is_owner"`
+	IsFollowing bool `json:"is_following"`
+}
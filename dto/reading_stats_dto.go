@@ -0,0 +1,45 @@
+package dto
+
+import "web-be/models"
+
+type ReadingDayResponse struct {
+	Date     string `json:"date"`
+	Chapters int    `json:"chapters"`
+	Words    int64  `json:"words"`
+	Minutes  int64  `json:"minutes"`
+}
+
+type ReadingStatsResponse struct {
+	From                string                     `json:"from"`
+	To                  string                     `json:"to"`
+	Chapters            int                        `json:"chapters"`
+	Words               int64                      `json:"words"`
+	Minutes             int64                      `json:"minutes"`
+	Stories             int                        `json:"stories"`
+	ActiveDays          int                        `json:"active_days"`
+	CurrentStreak       int                        `json:"current_streak"`
+	LongestStreak       int                        `json:"longest_streak"`
+	Daily               []ReadingDayResponse       `json:"daily"`
+	FavouriteCategories []models.CategoryReadCount `json:"favourite_categories"`
+}
+
+type ReadingMonthResponse struct {
+	Month    int   `json:"month"`
+	Chapters int   `json:"chapters"`
+	Words    int64 `json:"words"`
+}
+
+type ReadingRecapResponse struct {
+	Year           int                        `json:"year"`
+	Chapters       int                        `json:"chapters"`
+	Words          int64                      `json:"words"`
+	Minutes        int64                      `json:"minutes"`
+	Stories        int                        `json:"stories"`
+	StoriesStarted int                        `json:"stories_started"`
+	ActiveDays     int                        `json:"active_days"`
+	LongestStreak  int                        `json:"longest_streak"`
+	BusiestDay     *ReadingDayResponse        `json:"busiest_day,omitempty"`
+	Months         []ReadingMonthResponse     `json:"months"`
+	TopCategories  []models.CategoryReadCount `json:"top_categories"`
+	TopStories     []models.StoryReadCount    `json:"top_stories"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type SessionResponse struct {
+	ID         int       `json:"id"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	IPAddress  *string   `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+	// ImpersonatorID is set for support sessions opened by an admin
+	ImpersonatorID *int `json:"impersonator_id,omitempty"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
@@ -1,12 +1,13 @@
 package dto
 
 type CreateStoryRequest struct {
-	Title         string  `json:"title" binding:"required,min=1,max=255"`
-	Description   *string `json:"description"`
-	CoverImageURL *string `json:"cover_image_url"`
-	AuthorName    *string `json:"author_name"`
-	Status        string  `json:"status" binding:"omitempty,oneof=ongoing completed dropped"`
-	CategoryIDs   []int   `json:"category_ids"`
+	Title         string   `json:"title" binding:"required,min=1,max=255"`
+	Description   *string  `json:"description"`
+	CoverImageURL *string  `json:"cover_image_url"`
+	AuthorName    *string  `json:"author_name"`
+	Status        string   `json:"status" binding:"omitempty,oneof=ongoing completed dropped"`
+	CategoryIDs   []int    `json:"category_ids"`
+	Tags          []string `json:"tags" binding:"omitempty,max=10,dive,min=1,max=50"`
 }
 
 type UpdateStoryRequest struct {
@@ -16,6 +17,10 @@ type UpdateStoryRequest struct {
 	AuthorName    *string `json:"author_name"`
 	Status        *string `json:"status" binding:"omitempty,oneof=ongoing completed dropped"`
 	CategoryIDs   []int   `json:"category_ids"`
+	// Replaces the story's tags when present; an empty list clears them
+	Tags []string `json:"tags" binding:"omitempty,max=10,dive,min=1,max=50"`
+	// Keep the current slug when the title changes
+	KeepSlug bool `json:"keep_slug"`
 }
 
 type StoryResponse struct {
@@ -44,11 +49,29 @@ type CategoryResponse struct {
 }
 
 type CreateCategoryRequest struct {
-	Name        string  `json:"name" binding:"required,min=1,max=100"`
-	Description *string `json:"description"`
+	Name         string  `json:"name" binding:"required,min=1,max=100"`
+	Description  *string `json:"description"`
+	ParentID     *int    `json:"parent_id"`
+	DisplayOrder int     `json:"display_order"`
 }
 
+// UpdateCategoryRequest - parent_id 0 moves the category to the top level
 type UpdateCategoryRequest struct {
-	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
-	Description *string `json:"description"`
+	Name         *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description  *string `json:"description"`
+	ParentID     *int    `json:"parent_id" binding:"omitempty,min=0"`
+	DisplayOrder *int    `json:"display_order"`
+}
+
+type DeleteCategoryRequest struct {
+	ReassignTo *int `form:"reassign_to"`
+}
+
+type MergeCategoryRequest struct {
+	IntoID int `json:"into_id" binding:"required"`
+}
+
+// ReorderCategoriesRequest - display order follows the position in the list
+type ReorderCategoriesRequest struct {
+	CategoryIDs []int `json:"category_ids" binding:"required,min=1"`
 }
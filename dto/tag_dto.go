@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"strings"
+
+	"web-be/models"
+)
+
+type TagSearchRequest struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+type TagResponse struct {
+	models.Tag
+	Synonyms []models.Tag `json:"synonyms,omitempty"`
+}
+
+type AddTagSynonymRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=50"`
+}
+
+type MergeTagRequest struct {
+	Into string `json:"into" binding:"required"`
+}
+
+// StoryListRequest - story listing with an optional tag filter
+type StoryListRequest struct {
+	PaginationRequest
+	Tags string `form:"tags"`
+}
+
+func (r *StoryListRequest) TagSlugs() []string {
+	return splitTagSlugs(r.Tags)
+}
+
+// splitTagSlugs parses a comma-separated tag filter such as "xuyen-khong,he-thong"
+func splitTagSlugs(s string) []string {
+	var slugs []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			slugs = append(slugs, part)
+		}
+	}
+	return slugs
+}
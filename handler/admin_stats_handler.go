@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-be/dto"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type AdminStatsHandler struct {
+	statsService *service.AdminStatsService
+}
+
+func NewAdminStatsHandler(statsService *service.AdminStatsService) *AdminStatsHandler {
+	return &AdminStatsHandler{statsService: statsService}
+}
+
+// GetStats godoc
+// @Summary Site-wide dashboard metrics (admin)
+// @Description Live totals plus daily signups, DAU/MAU, new content, chapters read and views from the rollup, and the most viewed stories. Defaults to the last 30 days.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} dto.AdminStatsResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/stats [get]
+func (h *AdminStatsHandler) GetStats(c *gin.Context) {
+	var req dto.DateRangeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	stats, err := h.statsService.GetStats(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", stats)
+}
+
+// Refresh godoc
+// @Summary Recompute the dashboard rollup now (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Success 200 {object} utils.APIResponse
+// @Router /api/v1/admin/stats/refresh [post]
+func (h *AdminStatsHandler) Refresh(c *gin.Context) {
+	if err := h.statsService.RefreshRollup(c.Request.Context()); err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to refresh admin stats", "error", err)
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stats refreshed", nil)
+}
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type AdminUserHandler struct {
+	adminUserService *service.AdminUserService
+}
+
+func NewAdminUserHandler(adminUserService *service.AdminUserService) *AdminUserHandler {
+	return &AdminUserHandler{adminUserService: adminUserService}
+}
+
+// List godoc
+// @Summary Search users (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "Username, email or name contains"
+// @Param role query string false "Role (user, author, admin)"
+// @Param status query string false "Status (active, suspended, banned)"
+// @Param created_from query string false "Signed up on or after (YYYY-MM-DD)"
+// @Param created_to query string false "Signed up on or before (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /api/v1/admin/users [get]
+func (h *AdminUserHandler) List(c *gin.Context) {
+	var req dto.AdminUserFilterRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	req.Normalize()
+
+	users, total, err := h.adminUserService.List(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(users, req.Page, req.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// Get godoc
+// @Summary Get a user's account details (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/admin/users/{id} [get]
+func (h *AdminUserHandler) Get(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminUserService.Get(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", user)
+}
+
+// Suspend godoc
+// @Summary Suspend a user until a given time (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body dto.SuspendUserRequest true "Reason and end of suspension"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/users/{id}/suspend [post]
+func (h *AdminUserHandler) Suspend(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	user, err := h.adminUserService.Suspend(c.Request.Context(), adminActor(c), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User suspended", user)
+}
+
+// Unsuspend godoc
+// @Summary Lift a user's suspension (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/users/{id}/suspend [delete]
+func (h *AdminUserHandler) Unsuspend(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminUserService.Unsuspend(c.Request.Context(), adminActor(c), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Suspension lifted", user)
+}
+
+// Ban godoc
+// @Summary Permanently ban a user (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body dto.BanUserRequest true "Ban reason"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/users/{id}/ban [post]
+func (h *AdminUserHandler) Ban(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	user, err := h.adminUserService.Ban(c.Request.Context(), adminActor(c), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User banned", user)
+}
+
+// Unban godoc
+// @Summary Lift a user's ban (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/users/{id}/ban [delete]
+func (h *AdminUserHandler) Unban(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminUserService.Unban(c.Request.Context(), adminActor(c), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User unbanned", user)
+}
+
+// ChangeRole godoc
+// @Summary Change a user's role (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body dto.ChangeRoleRequest true "New role"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/users/{id}/role [put]
+func (h *AdminUserHandler) ChangeRole(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	user, err := h.adminUserService.ChangeRole(c.Request.Context(), adminActor(c), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Role updated", user)
+}
+
+// ForcePasswordReset godoc
+// @Summary Force a user to reset their password (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.AdminUserResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/users/{id}/force-password-reset [post]
+func (h *AdminUserHandler) ForcePasswordReset(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.adminUserService.ForcePasswordReset(c.Request.Context(), adminActor(c), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password reset required at next login", user)
+}
+
+// Impersonate godoc
+// @Summary Get a read-only token to act as a user for support (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body dto.ImpersonateRequest true "Support reason"
+// @Success 200 {object} dto.ImpersonationResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/users/{id}/impersonate [post]
+func (h *AdminUserHandler) Impersonate(c *gin.Context) {
+	userID, ok := parseUserIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	response, err := h.adminUserService.Impersonate(c.Request.Context(), adminActor(c), userID, &req, c.Request.UserAgent())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Impersonation session started", response)
+}
+
+// GetAuditLogs godoc
+// @Summary List admin actions on user accounts (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param user_id query int false "Only actions on this user"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /api/v1/admin/audit-logs [get]
+func (h *AdminUserHandler) GetAuditLogs(c *gin.Context) {
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	var targetUserID *int
+	if userIDStr := c.Query("user_id"); userIDStr != "" {
+		id, err := strconv.Atoi(userIDStr)
+		if err != nil {
+			_ = c.Error(apperror.Validation("invalid_user_id", "Invalid user ID"))
+			return
+		}
+		targetUserID = &id
+	}
+
+	logs, total, err := h.adminUserService.GetAuditLogs(c.Request.Context(), targetUserID, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(logs, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+func parseUserIDParam(c *gin.Context) (int, bool) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_user_id", "Invalid user ID"))
+		return 0, false
+	}
+	return userID, true
+}
+
+func adminActor(c *gin.Context) service.AdminActor {
+	userID, _ := middleware.GetUserID(c)
+	return service.AdminActor{UserID: userID, IPAddress: c.ClientIP()}
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/csv"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type AnalyticsHandler struct {
+	analyticsService *service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService *service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService}
+}
+
+// GetStoryAnalytics godoc
+// @Summary Analytics dashboard for a story (author or admin)
+// @Description Daily views, unique readers, new bookmarks and followers, plus per-chapter totals and drop-off. Defaults to the last 30 days.
+// @Tags analytics
+// @Security BearerAuth
+// @Produce json
+// @Param slug path string true "Story slug"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} dto.StoryAnalyticsResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/stories/{slug}/analytics [get]
+func (h *AnalyticsHandler) GetStoryAnalytics(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+	userRole, _ := middleware.GetUserRole(c)
+
+	var req dto.DateRangeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	analytics, err := h.analyticsService.GetStoryAnalytics(c.Request.Context(), c.Param("slug"), userID, userRole, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", analytics)
+}
+
+// GetChapterAnalytics godoc
+// @Summary Daily analytics for one chapter (author or admin)
+// @Tags analytics
+// @Security BearerAuth
+// @Produce json
+// @Param slug path string true "Story slug"
+// @Param chapter_num path int true "Chapter number"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} dto.ChapterDailyAnalyticsResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/stories/{slug}/analytics/chapters/{chapter_num} [get]
+func (h *AnalyticsHandler) GetChapterAnalytics(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+	userRole, _ := middleware.GetUserRole(c)
+
+	chapterNum, err := strconv.Atoi(c.Param("chapter_num"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_chapter_number", "Invalid chapter number"))
+		return
+	}
+
+	var req dto.DateRangeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	analytics, err := h.analyticsService.GetChapterAnalytics(c.Request.Context(), c.Param("slug"), chapterNum, userID, userRole, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", analytics)
+}
+
+// ExportCSV godoc
+// @Summary Export story analytics as CSV (author or admin)
+// @Tags analytics
+// @Security BearerAuth
+// @Produce text/csv
+// @Param slug path string true "Story slug"
+// @Param report query string false "daily (default) or chapters"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/stories/{slug}/analytics/export [get]
+func (h *AnalyticsHandler) ExportCSV(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+	userRole, _ := middleware.GetUserRole(c)
+
+	var req dto.ExportAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	filename, rows, err := h.analyticsService.ExportCSV(c.Request.Context(), c.Param("slug"), userID, userRole, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	if err := w.WriteAll(rows); err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to write analytics csv", "error", err, "slug", c.Param("slug"))
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type APITokenHandler struct {
+	tokenService *service.APITokenService
+}
+
+func NewAPITokenHandler(tokenService *service.APITokenService) *APITokenHandler {
+	return &APITokenHandler{tokenService: tokenService}
+}
+
+// Create godoc
+// @Summary Create a personal access token
+// @Tags tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPITokenRequest true "Token details"
+// @Success 201 {object} dto.CreatedAPITokenResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/me/tokens [post]
+func (h *APITokenHandler) Create(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.CreateAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	token, err := h.tokenService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Token created, copy it now as it will not be shown again", token)
+}
+
+// List godoc
+// @Summary List current user's personal access tokens
+// @Tags tokens
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.APITokenResponse
+// @Router /api/v1/me/tokens [get]
+func (h *APITokenHandler) List(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	tokens, err := h.tokenService.List(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", tokens)
+}
+
+// Revoke godoc
+// @Summary Revoke a personal access token
+// @Tags tokens
+// @Security BearerAuth
+// @Param id path int true "Token ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/me/tokens/{id} [delete]
+func (h *APITokenHandler) Revoke(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	tokenID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_token_id", "Invalid token ID"))
+		return
+	}
+
+	err = h.tokenService.Revoke(c.Request.Context(), userID, tokenID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token revoked", nil)
+}
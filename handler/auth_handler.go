@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"web-be/apperror"
 	"web-be/dto"
 	"web-be/middleware"
 	"web-be/service"
@@ -30,13 +31,13 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 
-	response, err := h.authService.Register(c.Request.Context(), &req)
+	response, err := h.authService.Register(c.Request.Context(), &req, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -55,13 +56,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 
-	response, err := h.authService.Login(c.Request.Context(), &req)
+	response, err := h.authService.Login(c.Request.Context(), &req, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusUnauthorized, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -79,13 +80,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 func (h *AuthHandler) GetProfile(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	profile, err := h.authService.GetProfile(c.Request.Context(), userID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		_ = c.Error(err)
 		return
 	}
 
@@ -105,48 +106,64 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	var req dto.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 
 	profile, err := h.authService.UpdateProfile(c.Request.Context(), userID, &req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Profile updated", profile)
 }
 
-// GetAllUsers godoc
-// @Summary Get all users (admin only)
-// @Tags admin
+// ChangePassword godoc
+// @Summary Change current user's password
+// @Tags auth
 // @Security BearerAuth
+// @Accept json
 // @Produce json
-// @Param page query int false "Page number"
-// @Param page_size query int false "Page size"
-// @Success 200 {object} utils.PaginatedResponse
-// @Router /api/v1/admin/users [get]
-func (h *AuthHandler) GetAllUsers(c *gin.Context) {
-	var pagination dto.PaginationRequest
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+// @Param request body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/me/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
-	pagination.Normalize()
+	sessionID, _ := middleware.GetSessionID(c)
 
-	users, total, err := h.authService.GetAllUsers(c.Request.Context(), pagination.GetLimit(), pagination.GetOffset())
-	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get users")
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 
-	response := utils.NewPaginatedResponse(users, pagination.Page, pagination.PageSize, total)
-	utils.SuccessResponse(c, http.StatusOK, "", response)
+	if err := h.authService.ChangePassword(c.Request.Context(), userID, sessionID, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password changed", nil)
+}
+
+// JWKS godoc
+// @Summary Public keys used to verify access tokens
+// @Tags auth
+// @Produce json
+// @Success 200 {object} utils.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.authService.JWKS())
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"web-be/apperror"
 	"web-be/dto"
 	"web-be/middleware"
 	"web-be/service"
@@ -31,21 +32,21 @@ func NewBookmarkHandler(bookmarkService *service.BookmarkService) *BookmarkHandl
 func (h *BookmarkHandler) AddBookmark(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	storyIDStr := c.Param("story_id")
 	storyID, err := strconv.Atoi(storyIDStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid story ID")
+		_ = c.Error(apperror.Validation("invalid_story_id", "Invalid story ID"))
 		return
 	}
 
 	err = h.bookmarkService.AddBookmark(c.Request.Context(), userID, storyID)
 	if err != nil {
-		slog.Warn("add bookmark failed", "error", err, "user_id", userID, "story_id", storyID)
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		slog.WarnContext(c.Request.Context(), "add bookmark failed", "error", err, "user_id", userID, "story_id", storyID)
+		_ = c.Error(err)
 		return
 	}
 
@@ -62,20 +63,20 @@ func (h *BookmarkHandler) AddBookmark(c *gin.Context) {
 func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	storyIDStr := c.Param("story_id")
 	storyID, err := strconv.Atoi(storyIDStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid story ID")
+		_ = c.Error(apperror.Validation("invalid_story_id", "Invalid story ID"))
 		return
 	}
 
 	err = h.bookmarkService.RemoveBookmark(c.Request.Context(), userID, storyID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -94,20 +95,20 @@ func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
 func (h *BookmarkHandler) GetMyBookmarks(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	var pagination dto.PaginationRequest
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 	pagination.Normalize()
 
 	bookmarks, total, err := h.bookmarkService.GetUserBookmarks(c.Request.Context(), userID, pagination.GetLimit(), pagination.GetOffset())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get bookmarks")
+		_ = c.Error(err)
 		return
 	}
 
@@ -125,20 +126,20 @@ func (h *BookmarkHandler) GetMyBookmarks(c *gin.Context) {
 func (h *BookmarkHandler) GetBookmarkStatus(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	storyIDStr := c.Param("story_id")
 	storyID, err := strconv.Atoi(storyIDStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid story ID")
+		_ = c.Error(apperror.Validation("invalid_story_id", "Invalid story ID"))
 		return
 	}
 
 	isBookmarked, totalBookmarks, err := h.bookmarkService.GetBookmarkStatus(c.Request.Context(), userID, storyID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get bookmark status")
+		_ = c.Error(err)
 		return
 	}
 
@@ -160,7 +161,7 @@ func (h *BookmarkHandler) GetViewStats(c *gin.Context) {
 
 	story, err := h.bookmarkService.GetStoryViewStats(c.Request.Context(), slug)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
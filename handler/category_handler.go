@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type CategoryHandler struct {
+	categoryService *service.CategoryService
+	storyService    *service.StoryService
+}
+
+func NewCategoryHandler(categoryService *service.CategoryService, storyService *service.StoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+		storyService:    storyService,
+	}
+}
+
+// GetAll godoc
+// @Summary Get the category tree with story counts
+// @Tags categories
+// @Produce json
+// @Success 200 {array} models.CategoryNode
+// @Router /api/v1/categories [get]
+func (h *CategoryHandler) GetAll(c *gin.Context) {
+	categories, err := h.categoryService.GetTree(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", categories)
+}
+
+// GetStories godoc
+// @Summary Get stories by category (includes subcategories)
+// @Description Old slugs of renamed or merged categories answer with 301 to the current URL
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Failure 301 "Category moved"
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/categories/{slug}/stories [get]
+func (h *CategoryHandler) GetStories(c *gin.Context) {
+	slug := c.Param("slug")
+
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	category, err := h.categoryService.GetBySlug(c.Request.Context(), slug)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	if category.Slug != slug {
+		location := "/api/v1/categories/" + category.Slug + "/stories"
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+
+	stories, total, err := h.storyService.GetByCategory(c.Request.Context(), category.ID, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(stories, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// Create godoc
+// @Summary Create a new category (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCategoryRequest true "Category details"
+// @Success 201 {object} models.Category
+// @Router /api/v1/admin/categories [post]
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req dto.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	category, err := h.categoryService.Create(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Category created", category)
+}
+
+// Update godoc
+// @Summary Rename, move or reorder a category (admin only)
+// @Description Renaming changes the slug; the old slug keeps redirecting
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param request body dto.UpdateCategoryRequest true "Fields to change"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/categories/{id} [put]
+func (h *CategoryHandler) Update(c *gin.Context) {
+	id, ok := parseCategoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	category, err := h.categoryService.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category updated", category)
+}
+
+// Delete godoc
+// @Summary Delete a category (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param reassign_to query int false "Move the category's stories here first"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/categories/{id} [delete]
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id, ok := parseCategoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.DeleteCategoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	if err := h.categoryService.Delete(c.Request.Context(), id, &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category deleted", nil)
+}
+
+// Merge godoc
+// @Summary Merge a category into another (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID to merge away"
+// @Param request body dto.MergeCategoryRequest true "Target category"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/categories/{id}/merge [post]
+func (h *CategoryHandler) Merge(c *gin.Context) {
+	id, ok := parseCategoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MergeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	category, err := h.categoryService.Merge(c.Request.Context(), id, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categories merged", category)
+}
+
+// Reorder godoc
+// @Summary Set category display order (admin only)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.ReorderCategoriesRequest true "Category IDs in display order"
+// @Success 200 {object} utils.APIResponse
+// @Router /api/v1/admin/categories/order [put]
+func (h *CategoryHandler) Reorder(c *gin.Context) {
+	var req dto.ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	if err := h.categoryService.Reorder(c.Request.Context(), &req); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categories reordered", nil)
+}
+
+func parseCategoryIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_category_id", "Invalid category ID"))
+		return 0, false
+	}
+	return id, true
+}
@@ -2,9 +2,12 @@ package handler
 
 import (
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"web-be/apperror"
 	"web-be/dto"
 	"web-be/middleware"
 	"web-be/service"
@@ -21,6 +24,7 @@ func NewChapterHandler(chapterService *service.ChapterService) *ChapterHandler {
 
 // GetByStory godoc
 // @Summary Get chapters list by story
+// @Description Authenticated readers also get is_read and progress_percent per chapter.
 // @Tags chapters
 // @Produce json
 // @Param slug path string true "Story slug"
@@ -30,17 +34,18 @@ func NewChapterHandler(chapterService *service.ChapterService) *ChapterHandler {
 // @Router /api/v1/stories/{slug}/chapters [get]
 func (h *ChapterHandler) GetByStory(c *gin.Context) {
 	storySlug := c.Param("slug")
+	userID, _ := middleware.GetUserID(c)
 
 	var pagination dto.PaginationRequest
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 	pagination.Normalize()
 
-	chapters, total, err := h.chapterService.GetListByStory(c.Request.Context(), storySlug, pagination.GetLimit(), pagination.GetOffset())
+	chapters, total, err := h.chapterService.GetListByStory(c.Request.Context(), storySlug, userID, pagination.GetLimit(), pagination.GetOffset())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -50,32 +55,121 @@ func (h *ChapterHandler) GetByStory(c *gin.Context) {
 
 // GetChapter godoc
 // @Summary Get a chapter content
+// @Description Authenticated readers have the read recorded and get their saved position back.
 // @Tags chapters
 // @Produce json
 // @Param slug path string true "Story slug"
-// @Param chapter_num path int true "Chapter number"
+// @Param chapter_num path string true "Chapter number; a chapter slug, current or former, answers 301 to the numbered URL"
+// @Param If-None-Match header string false "ETag of a copy the client holds"
 // @Success 200 {object} dto.ChapterResponse
+// @Failure 301 "Chapter addressed by slug"
+// @Failure 304 "The client's copy is current"
 // @Failure 404 {object} utils.APIResponse
 // @Router /api/v1/stories/{slug}/chapters/{chapter_num} [get]
 func (h *ChapterHandler) GetChapter(c *gin.Context) {
 	storySlug := c.Param("slug")
+	userID, _ := middleware.GetUserID(c)
 	chapterNumStr := c.Param("chapter_num")
 
 	chapterNum, err := strconv.Atoi(chapterNumStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid chapter number")
+		chapterNum, err = h.chapterService.ResolveSlug(c.Request.Context(), storySlug, chapterNumStr)
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		// Swap only the chapter segment so the mount point and query string survive
+		location := *c.Request.URL
+		location.Path = path.Join(path.Dir(strings.TrimSuffix(location.Path, "/")), strconv.Itoa(chapterNum))
+		location.RawPath = ""
+		c.Redirect(http.StatusMovedPermanently, location.RequestURI())
 		return
 	}
 
-	chapter, err := h.chapterService.GetByStoryAndNumber(c.Request.Context(), storySlug, chapterNum)
+	_, impersonating := middleware.GetImpersonatorID(c)
+	chapter, err := h.chapterService.GetByStoryAndNumber(c.Request.Context(), storySlug, chapterNum, userID, !impersonating)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "", chapter)
 }
 
+// SaveProgress godoc
+// @Summary Save the reading position inside a chapter
+// @Description The chapter counts as read once progress_percent reaches 90.
+// @Tags chapters
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param slug path string true "Story slug"
+// @Param chapter_num path int true "Chapter number"
+// @Param request body dto.UpdateReadingProgressRequest true "Position"
+// @Success 200 {object} dto.ReadingProgressResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/stories/{slug}/chapters/{chapter_num}/progress [put]
+func (h *ChapterHandler) SaveProgress(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	chapterNum, err := strconv.Atoi(c.Param("chapter_num"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_chapter_number", "Invalid chapter number"))
+		return
+	}
+
+	var req dto.UpdateReadingProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	progress, err := h.chapterService.SaveProgress(c.Request.Context(), c.Param("slug"), chapterNum, userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", progress)
+}
+
+// GetContinueReading godoc
+// @Summary Stories in progress with the next chapter to read
+// @Tags chapters
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /api/v1/me/continue-reading [get]
+func (h *ChapterHandler) GetContinueReading(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	items, total, err := h.chapterService.GetContinueReading(c.Request.Context(), userID, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(items, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
 // Create godoc
 // @Summary Create a new chapter
 // @Tags chapters
@@ -90,7 +184,7 @@ func (h *ChapterHandler) GetChapter(c *gin.Context) {
 func (h *ChapterHandler) Create(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 	userRole, _ := middleware.GetUserRole(c)
@@ -99,13 +193,13 @@ func (h *ChapterHandler) Create(c *gin.Context) {
 
 	var req dto.CreateChapterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 
 	chapter, err := h.chapterService.Create(c.Request.Context(), storySlug, userID, userRole, &req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -127,7 +221,7 @@ func (h *ChapterHandler) Create(c *gin.Context) {
 func (h *ChapterHandler) Update(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 	userRole, _ := middleware.GetUserRole(c)
@@ -137,19 +231,19 @@ func (h *ChapterHandler) Update(c *gin.Context) {
 
 	chapterNum, err := strconv.Atoi(chapterNumStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid chapter number")
+		_ = c.Error(apperror.Validation("invalid_chapter_number", "Invalid chapter number"))
 		return
 	}
 
 	var req dto.UpdateChapterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 
 	chapter, err := h.chapterService.Update(c.Request.Context(), storySlug, chapterNum, userID, userRole, &req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -158,6 +252,7 @@ func (h *ChapterHandler) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary Delete a chapter
+// @Description Moves the chapter to the trash; it can be restored until the retention window passes.
 // @Tags chapters
 // @Security BearerAuth
 // @Param slug path string true "Story slug"
@@ -168,7 +263,7 @@ func (h *ChapterHandler) Update(c *gin.Context) {
 func (h *ChapterHandler) Delete(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 	userRole, _ := middleware.GetUserRole(c)
@@ -178,13 +273,13 @@ func (h *ChapterHandler) Delete(c *gin.Context) {
 
 	chapterNum, err := strconv.Atoi(chapterNumStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid chapter number")
+		_ = c.Error(apperror.Validation("invalid_chapter_number", "Invalid chapter number"))
 		return
 	}
 
 	err = h.chapterService.Delete(c.Request.Context(), storySlug, chapterNum, userID, userRole)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type FollowHandler struct {
+	followService *service.FollowService
+}
+
+func NewFollowHandler(followService *service.FollowService) *FollowHandler {
+	return &FollowHandler{followService: followService}
+}
+
+// Follow godoc
+// @Summary Follow an author
+// @Tags follows
+// @Security BearerAuth
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {object} dto.FollowStatusResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/users/{username}/follow [post]
+func (h *FollowHandler) Follow(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	status, err := h.followService.Follow(c.Request.Context(), userID, c.Param("username"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Followed", status)
+}
+
+// Unfollow godoc
+// @Summary Unfollow an author
+// @Tags follows
+// @Security BearerAuth
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {object} dto.FollowStatusResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/users/{username}/follow [delete]
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	status, err := h.followService.Unfollow(c.Request.Context(), userID, c.Param("username"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unfollowed", status)
+}
+
+// GetStatus godoc
+// @Summary Check whether the current user follows an author
+// @Tags follows
+// @Security BearerAuth
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {object} dto.FollowStatusResponse
+// @Router /api/v1/users/{username}/follow [get]
+func (h *FollowHandler) GetStatus(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	status, err := h.followService.GetStatus(c.Request.Context(), userID, c.Param("username"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", status)
+}
+
+// GetFollowers godoc
+// @Summary List a user's followers
+// @Description Only the user can see the list unless their profile shows followers.
+// @Tags follows
+// @Produce json
+// @Param username path string true "Username"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /api/v1/users/{username}/followers [get]
+func (h *FollowHandler) GetFollowers(c *gin.Context) {
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	viewerID, _ := middleware.GetUserID(c)
+	users, total, err := h.followService.GetFollowers(c.Request.Context(), viewerID, c.Param("username"), pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(users, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// GetFollowing godoc
+// @Summary List the authors a user follows
+// @Description Only the user can see the list unless their profile shows followers.
+// @Tags follows
+// @Produce json
+// @Param username path string true "Username"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Failure 403 {object} utils.APIResponse
+// @Router /api/v1/users/{username}/following [get]
+func (h *FollowHandler) GetFollowing(c *gin.Context) {
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	viewerID, _ := middleware.GetUserID(c)
+	users, total, err := h.followService.GetFollowing(c.Request.Context(), viewerID, c.Param("username"), pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(users, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// GetFeed godoc
+// @Summary Activity feed of new stories and chapters from followed authors and stories
+// @Tags follows
+// @Security BearerAuth
+// @Produce json
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Items per page (max 50)"
+// @Success 200 {object} dto.FeedResponse
+// @Router /api/v1/feed [get]
+func (h *FollowHandler) GetFeed(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.FeedRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	feed, err := h.followService.GetFeed(c.Request.Context(), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", feed)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"web-be/db"
+)
+
+// readyTimeout bounds the database checks behind /readyz
+const readyTimeout = 2 * time.Second
+
+type HealthHandler struct {
+	db            *sqlx.DB
+	migrationsDir string
+}
+
+func NewHealthHandler(database *sqlx.DB, migrationsDir string) *HealthHandler {
+	return &HealthHandler{db: database, migrationsDir: migrationsDir}
+}
+
+// ReadinessResponse reports the dependencies a ready instance needs
+type ReadinessResponse struct {
+	Status     string             `json:"status"`
+	Database   string             `json:"database"`
+	Migrations *db.MigrationState `json:"migrations,omitempty"`
+}
+
+// Livez godoc
+// @Summary Liveness probe
+// @Description Succeeds while the process is serving requests; it does not check dependencies
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz godoc
+// @Summary Readiness probe
+// @Description Pings the database and checks that every migration is applied
+// @Tags health
+// @Produce json
+// @Success 200 {object} ReadinessResponse
+// @Failure 503 {object} ReadinessResponse
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+	defer cancel()
+
+	response := ReadinessResponse{Status: "ok", Database: "ok"}
+	if err := h.db.PingContext(ctx); err != nil {
+		slog.WarnContext(c.Request.Context(), "readiness check failed", "check", "database", "error", err)
+		response.Status, response.Database = "unavailable", "unreachable"
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	state, err := db.Migrations(ctx, h.db, h.migrationsDir)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "readiness check failed", "check", "migrations", "error", err)
+		response.Status = "unavailable"
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+	response.Migrations = state
+	if len(state.Pending) > 0 {
+		response.Status = "unavailable"
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
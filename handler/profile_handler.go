@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type ProfileHandler struct {
+	profileService *service.ProfileService
+}
+
+func NewProfileHandler(profileService *service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{profileService: profileService}
+}
+
+// GetPublicProfile godoc
+// @Summary Get an author's public profile
+// @Tags users
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {object} dto.PublicProfileResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/users/{username} [get]
+func (h *ProfileHandler) GetPublicProfile(c *gin.Context) {
+	viewerID, _ := middleware.GetUserID(c)
+
+	profile, err := h.profileService.GetPublicProfile(c.Request.Context(), c.Param("username"), viewerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", profile)
+}
+
+// GetAuthorStories godoc
+// @Summary Get an author's published stories
+// @Tags users
+// @Produce json
+// @Param username path string true "Username"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /api/v1/users/{username}/stories [get]
+func (h *ProfileHandler) GetAuthorStories(c *gin.Context) {
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	stories, total, err := h.profileService.GetAuthorStories(c.Request.Context(), c.Param("username"), pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(stories, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// GetSettings godoc
+// @Summary Get current user's public profile settings
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} models.UserProfile
+// @Router /api/v1/me/profile [get]
+func (h *ProfileHandler) GetSettings(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	profile, err := h.profileService.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", profile)
+}
+
+// UpdateSettings godoc
+// @Summary Update current user's bio, social links and privacy settings
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdatePublicProfileRequest true "Profile settings"
+// @Success 200 {object} models.UserProfile
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/me/profile [put]
+func (h *ProfileHandler) UpdateSettings(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.UpdatePublicProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	profile, err := h.profileService.UpdateSettings(c.Request.Context(), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Profile updated", profile)
+}
@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type ReadingListHandler struct {
+	listService *service.ReadingListService
+}
+
+func NewReadingListHandler(listService *service.ReadingListService) *ReadingListHandler {
+	return &ReadingListHandler{listService: listService}
+}
+
+// GetMyLists godoc
+// @Summary List the current user's reading lists
+// @Description Built-in lists come first. Pass story_id to see which lists contain that story.
+// @Tags reading-lists
+// @Security BearerAuth
+// @Produce json
+// @Param story_id query int false "Story ID"
+// @Success 200 {array} models.ReadingListSummary
+// @Router /api/v1/me/lists [get]
+func (h *ReadingListHandler) GetMyLists(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.MyReadingListsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	lists, err := h.listService.GetMyLists(c.Request.Context(), userID, req.StoryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", lists)
+}
+
+// Create godoc
+// @Summary Create a reading list
+// @Tags reading-lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateReadingListRequest true "Reading list"
+// @Success 201 {object} dto.ReadingListResponse
+// @Router /api/v1/me/lists [post]
+func (h *ReadingListHandler) Create(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.CreateReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	list, err := h.listService.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Reading list created", list)
+}
+
+// GetFollowedLists godoc
+// @Summary List the public reading lists the current user follows
+// @Tags reading-lists
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /api/v1/me/followed-lists [get]
+func (h *ReadingListHandler) GetFollowedLists(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	lists, total, err := h.listService.GetFollowedLists(c.Request.Context(), userID, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(lists, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// Get godoc
+// @Summary Get a reading list by its share code
+// @Description Public lists are visible to anyone; private lists only to their owner.
+// @Tags reading-lists
+// @Produce json
+// @Param code path string true "Share code"
+// @Success 200 {object} dto.ReadingListResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/lists/{code} [get]
+func (h *ReadingListHandler) Get(c *gin.Context) {
+	viewerID, _ := middleware.GetUserID(c)
+
+	list, err := h.listService.Get(c.Request.Context(), viewerID, c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", list)
+}
+
+// GetItems godoc
+// @Summary List the stories in a reading list, in list order
+// @Tags reading-lists
+// @Produce json
+// @Param code path string true "Share code"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/lists/{code}/items [get]
+func (h *ReadingListHandler) GetItems(c *gin.Context) {
+	viewerID, _ := middleware.GetUserID(c)
+
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	items, total, err := h.listService.GetItems(c.Request.Context(), viewerID, c.Param("code"), pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(items, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// Update godoc
+// @Summary Rename a reading list or change its visibility
+// @Tags reading-lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param code path string true "Share code"
+// @Param request body dto.UpdateReadingListRequest true "Fields to update"
+// @Success 200 {object} dto.ReadingListResponse
+// @Router /api/v1/lists/{code} [put]
+func (h *ReadingListHandler) Update(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.UpdateReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	list, err := h.listService.Update(c.Request.Context(), userID, c.Param("code"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reading list updated", list)
+}
+
+// Delete godoc
+// @Summary Delete a custom reading list
+// @Tags reading-lists
+// @Security BearerAuth
+// @Param code path string true "Share code"
+// @Success 200 {object} utils.APIResponse
+// @Router /api/v1/lists/{code} [delete]
+func (h *ReadingListHandler) Delete(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	if err := h.listService.Delete(c.Request.Context(), userID, c.Param("code")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reading list deleted", nil)
+}
+
+// AddItem godoc
+// @Summary Add a story to the end of a reading list
+// @Tags reading-lists
+// @Security BearerAuth
+// @Accept json
+// @Param code path string true "Share code"
+// @Param request body dto.ReadingListItemRequest true "Story"
+// @Success 200 {object} utils.APIResponse
+// @Router /api/v1/lists/{code}/items [post]
+func (h *ReadingListHandler) AddItem(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.ReadingListItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	if err := h.listService.AddItem(c.Request.Context(), userID, c.Param("code"), req.StoryID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Story added to reading list", nil)
+}
+
+// RemoveItem godoc
+// @Summary Remove a story from a reading list
+// @Tags reading-lists
+// @Security BearerAuth
+// @Param code path string true "Share code"
+// @Param story_id path int true "Story ID"
+// @Success 200 {object} utils.APIResponse
+// @Router /api/v1/lists/{code}/items/{story_id} [delete]
+func (h *ReadingListHandler) RemoveItem(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	storyID, err := strconv.Atoi(c.Param("story_id"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_story_id", "Invalid story ID"))
+		return
+	}
+
+	if err := h.listService.RemoveItem(c.Request.Context(), userID, c.Param("code"), storyID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Story removed from reading list", nil)
+}
+
+// Reorder godoc
+// @Summary Reorder a reading list
+// @Description Listed stories move to the top in the given order; the rest keep their order after them.
+// @Tags reading-lists
+// @Security BearerAuth
+// @Accept json
+// @Param code path string true "Share code"
+// @Param request body dto.ReorderReadingListRequest true "Story order"
+// @Success 200 {object} utils.APIResponse
+// @Router /api/v1/lists/{code}/items/order [put]
+func (h *ReadingListHandler) Reorder(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.ReorderReadingListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	if err := h.listService.Reorder(c.Request.Context(), userID, c.Param("code"), req.StoryIDs); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reading list reordered", nil)
+}
+
+// Follow godoc
+// @Summary Follow a public reading list
+// @Tags reading-lists
+// @Security BearerAuth
+// @Produce json
+// @Param code path string true "Share code"
+// @Success 200 {object} dto.ReadingListResponse
+// @Router /api/v1/lists/{code}/follow [post]
+func (h *ReadingListHandler) Follow(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	list, err := h.listService.Follow(c.Request.Context(), userID, c.Param("code"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Following reading list", list)
+}
+
+// Unfollow godoc
+// @Summary Unfollow a reading list
+// @Tags reading-lists
+// @Security BearerAuth
+// @Param code path string true "Share code"
+// @Success 200 {object} utils.APIResponse
+// @Router /api/v1/lists/{code}/follow [delete]
+func (h *ReadingListHandler) Unfollow(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	if err := h.listService.Unfollow(c.Request.Context(), userID, c.Param("code")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unfollowed reading list", nil)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type ReadingStatsHandler struct {
+	statsService *service.ReadingStatsService
+}
+
+func NewReadingStatsHandler(statsService *service.ReadingStatsService) *ReadingStatsHandler {
+	return &ReadingStatsHandler{statsService: statsService}
+}
+
+// GetStats godoc
+// @Summary Reading statistics for the current user
+// @Description Chapters, words and estimated minutes read per day, streaks and favourite categories. Defaults to the last 30 days.
+// @Tags stats
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} dto.ReadingStatsResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/me/stats [get]
+func (h *ReadingStatsHandler) GetStats(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	var req dto.DateRangeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	stats, err := h.statsService.GetStats(c.Request.Context(), userID, &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", stats)
+}
+
+// GetRecap godoc
+// @Summary Yearly reading recap for the current user
+// @Tags stats
+// @Security BearerAuth
+// @Produce json
+// @Param year path int true "Year"
+// @Success 200 {object} dto.ReadingRecapResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/me/recap/{year} [get]
+func (h *ReadingStatsHandler) GetRecap(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_year", "Invalid year"))
+		return
+	}
+
+	recap, err := h.statsService.GetRecap(c.Request.Context(), userID, year)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", recap)
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type SessionHandler struct {
+	sessionService *service.SessionService
+}
+
+func NewSessionHandler(sessionService *service.SessionService) *SessionHandler {
+	return &SessionHandler{sessionService: sessionService}
+}
+
+// List godoc
+// @Summary List current user's active sessions
+// @Tags sessions
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} dto.SessionResponse
+// @Router /api/v1/me/sessions [get]
+func (h *SessionHandler) List(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+	sessionID, _ := middleware.GetSessionID(c)
+
+	sessions, err := h.sessionService.List(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", sessions)
+}
+
+// Revoke godoc
+// @Summary Revoke one of the current user's sessions
+// @Tags sessions
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/me/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+
+	sessionID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_session_id", "Invalid session ID"))
+		return
+	}
+
+	err = h.sessionService.Revoke(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Session revoked", nil)
+}
+
+// RevokeOthers godoc
+// @Summary Sign out of all other sessions
+// @Tags sessions
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} dto.RevokeSessionsResponse
+// @Router /api/v1/me/sessions [delete]
+func (h *SessionHandler) RevokeOthers(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+	sessionID, _ := middleware.GetSessionID(c)
+
+	count, err := h.sessionService.RevokeOthers(c.Request.Context(), userID, sessionID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Other sessions revoked", dto.RevokeSessionsResponse{Revoked: count})
+}
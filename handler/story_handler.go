@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"web-be/apperror"
 	"web-be/dto"
 	"web-be/middleware"
 	"web-be/service"
@@ -25,23 +26,24 @@ func NewStoryHandler(storyService *service.StoryService) *StoryHandler {
 // @Produce json
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
+// @Param tags query string false "Comma-separated tag slugs; stories must have all of them"
 // @Success 200 {object} utils.PaginatedResponse
 // @Router /api/v1/stories [get]
 func (h *StoryHandler) GetAll(c *gin.Context) {
-	var pagination dto.PaginationRequest
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+	var req dto.StoryListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
-	pagination.Normalize()
+	req.Normalize()
 
-	stories, total, err := h.storyService.GetAll(c.Request.Context(), pagination.GetLimit(), pagination.GetOffset())
+	stories, total, err := h.storyService.GetAll(c.Request.Context(), req.TagSlugs(), req.GetLimit(), req.GetOffset())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get stories")
+		_ = c.Error(err)
 		return
 	}
 
-	response := utils.NewPaginatedResponse(stories, pagination.Page, pagination.PageSize, total)
+	response := utils.NewPaginatedResponse(stories, req.Page, req.PageSize, total)
 	utils.SuccessResponse(c, http.StatusOK, "", response)
 }
 
@@ -50,7 +52,9 @@ func (h *StoryHandler) GetAll(c *gin.Context) {
 // @Tags stories
 // @Produce json
 // @Param slug path string true "Story slug"
+// @Param If-None-Match header string false "ETag of a copy the client holds"
 // @Success 200 {object} models.Story
+// @Failure 304 "The client's copy is current"
 // @Failure 404 {object} utils.APIResponse
 // @Router /api/v1/stories/{slug} [get]
 func (h *StoryHandler) GetBySlug(c *gin.Context) {
@@ -58,7 +62,7 @@ func (h *StoryHandler) GetBySlug(c *gin.Context) {
 
 	story, err := h.storyService.GetBySlug(c.Request.Context(), slug)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -70,6 +74,7 @@ func (h *StoryHandler) GetBySlug(c *gin.Context) {
 // @Tags stories
 // @Produce json
 // @Param q query string true "Search keyword"
+// @Param tags query string false "Comma-separated tag slugs; stories must have all of them"
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
 // @Success 200 {object} utils.PaginatedResponse
@@ -77,14 +82,14 @@ func (h *StoryHandler) GetBySlug(c *gin.Context) {
 func (h *StoryHandler) Search(c *gin.Context) {
 	var req dto.SearchRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 	req.Normalize()
 
-	stories, total, err := h.storyService.Search(c.Request.Context(), req.Query, req.GetLimit(), req.GetOffset())
+	stories, total, err := h.storyService.Search(c.Request.Context(), req.Query, req.TagSlugs(), req.GetLimit(), req.GetOffset())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to search stories")
+		_ = c.Error(err)
 		return
 	}
 
@@ -105,19 +110,19 @@ func (h *StoryHandler) Search(c *gin.Context) {
 func (h *StoryHandler) Create(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	var req dto.CreateStoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 
 	story, err := h.storyService.Create(c.Request.Context(), userID, &req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -138,7 +143,7 @@ func (h *StoryHandler) Create(c *gin.Context) {
 func (h *StoryHandler) Update(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 	userRole, _ := middleware.GetUserRole(c)
@@ -147,13 +152,13 @@ func (h *StoryHandler) Update(c *gin.Context) {
 
 	var req dto.UpdateStoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 
 	story, err := h.storyService.Update(c.Request.Context(), slug, userID, userRole, &req)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -162,6 +167,7 @@ func (h *StoryHandler) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary Delete a story
+// @Description Moves the story to the trash; it can be restored until the retention window passes.
 // @Tags stories
 // @Security BearerAuth
 // @Param slug path string true "Story slug"
@@ -171,7 +177,7 @@ func (h *StoryHandler) Update(c *gin.Context) {
 func (h *StoryHandler) Delete(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 	userRole, _ := middleware.GetUserRole(c)
@@ -180,81 +186,38 @@ func (h *StoryHandler) Delete(c *gin.Context) {
 
 	err := h.storyService.Delete(c.Request.Context(), slug, userID, userRole)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Story deleted successfully", nil)
 }
 
-// GetByCategory godoc
-// @Summary Get stories by category
-// @Tags categories
-// @Produce json
-// @Param slug path string true "Category slug"
-// @Param page query int false "Page number"
-// @Param page_size query int false "Page size"
-// @Success 200 {object} utils.PaginatedResponse
-// @Router /api/v1/categories/{slug}/stories [get]
-func (h *StoryHandler) GetByCategory(c *gin.Context) {
-	slug := c.Param("slug")
-
-	var pagination dto.PaginationRequest
-	if err := c.ShouldBindQuery(&pagination); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
-		return
-	}
-	pagination.Normalize()
-
-	stories, total, err := h.storyService.GetByCategory(c.Request.Context(), slug, pagination.GetLimit(), pagination.GetOffset())
-	if err != nil {
-		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
-		return
-	}
-
-	response := utils.NewPaginatedResponse(stories, pagination.Page, pagination.PageSize, total)
-	utils.SuccessResponse(c, http.StatusOK, "", response)
-}
-
-// GetAllCategories godoc
-// @Summary Get all categories
-// @Tags categories
-// @Produce json
-// @Success 200 {array} models.Category
-// @Router /api/v1/categories [get]
-func (h *StoryHandler) GetAllCategories(c *gin.Context) {
-	categories, err := h.storyService.GetAllCategories(c.Request.Context())
-	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get categories")
-		return
-	}
-
-	utils.SuccessResponse(c, http.StatusOK, "", categories)
-}
-
-// CreateCategory godoc
-// @Summary Create a new category (admin only)
-// @Tags admin
+// SubmitForReview godoc
+// @Summary Submit a story for an admin to publish
+// @Description Adds the author's unpublished story to the moderation queue counted in the admin stats.
+// @Tags stories
 // @Security BearerAuth
-// @Accept json
 // @Produce json
-// @Param request body dto.CreateCategoryRequest true "Category details"
-// @Success 201 {object} models.Category
-// @Router /api/v1/admin/categories [post]
-func (h *StoryHandler) CreateCategory(c *gin.Context) {
-	var req dto.CreateCategoryRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+// @Param slug path string true "Story slug"
+// @Success 200 {object} models.Story
+// @Failure 403 {object} utils.APIResponse
+// @Failure 409 {object} utils.APIResponse
+// @Router /api/v1/stories/{slug}/submit [post]
+func (h *StoryHandler) SubmitForReview(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
-	category, err := h.storyService.CreateCategory(c.Request.Context(), &req)
+	story, err := h.storyService.SubmitForReview(c.Request.Context(), c.Param("slug"), userID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusCreated, "Category created", category)
+	utils.SuccessResponse(c, http.StatusOK, "Story submitted for review", story)
 }
 
 // Publish godoc
@@ -269,7 +232,7 @@ func (h *StoryHandler) Publish(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid story ID")
+		_ = c.Error(apperror.Validation("invalid_story_id", "Invalid story ID"))
 		return
 	}
 
@@ -277,7 +240,7 @@ func (h *StoryHandler) Publish(c *gin.Context) {
 
 	err = h.storyService.Publish(c.Request.Context(), id, publish)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -300,20 +263,20 @@ func (h *StoryHandler) Publish(c *gin.Context) {
 func (h *StoryHandler) GetReadingHistory(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	var pagination dto.PaginationRequest
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 	pagination.Normalize()
 
 	history, total, err := h.storyService.GetReadingHistory(c.Request.Context(), userID, pagination.GetLimit(), pagination.GetOffset())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get reading history")
+		_ = c.Error(err)
 		return
 	}
 
@@ -332,14 +295,14 @@ func (h *StoryHandler) GetReadingHistory(c *gin.Context) {
 func (h *StoryHandler) UpdateReadingHistory(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	storyIDStr := c.Param("story_id")
 	storyID, err := strconv.Atoi(storyIDStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid story ID")
+		_ = c.Error(apperror.Validation("invalid_story_id", "Invalid story ID"))
 		return
 	}
 
@@ -353,7 +316,7 @@ func (h *StoryHandler) UpdateReadingHistory(c *gin.Context) {
 
 	err = h.storyService.UpdateReadingHistory(c.Request.Context(), userID, storyID, chapterID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -370,20 +333,20 @@ func (h *StoryHandler) UpdateReadingHistory(c *gin.Context) {
 func (h *StoryHandler) DeleteReadingHistory(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	storyIDStr := c.Param("story_id")
 	storyID, err := strconv.Atoi(storyIDStr)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid story ID")
+		_ = c.Error(apperror.Validation("invalid_story_id", "Invalid story ID"))
 		return
 	}
 
 	err = h.storyService.DeleteReadingHistory(c.Request.Context(), userID, storyID)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(err)
 		return
 	}
 
@@ -402,20 +365,20 @@ func (h *StoryHandler) DeleteReadingHistory(c *gin.Context) {
 func (h *StoryHandler) GetMyStories(c *gin.Context) {
 	userID, ok := middleware.GetUserID(c)
 	if !ok {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Unauthorized")
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
 		return
 	}
 
 	var pagination dto.PaginationRequest
 	if err := c.ShouldBindQuery(&pagination); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		_ = c.Error(utils.BindingError(err))
 		return
 	}
 	pagination.Normalize()
 
 	stories, total, err := h.storyService.GetByAuthor(c.Request.Context(), userID, pagination.GetLimit(), pagination.GetOffset())
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get stories")
+		_ = c.Error(err)
 		return
 	}
 
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"web-be/dto"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type TagHandler struct {
+	tagService   *service.TagService
+	storyService *service.StoryService
+}
+
+func NewTagHandler(tagService *service.TagService, storyService *service.StoryService) *TagHandler {
+	return &TagHandler{
+		tagService:   tagService,
+		storyService: storyService,
+	}
+}
+
+// Search godoc
+// @Summary Autocomplete tags by prefix
+// @Tags tags
+// @Produce json
+// @Param q query string false "Tag prefix"
+// @Param limit query int false "Max results (default 10)"
+// @Success 200 {array} models.Tag
+// @Router /api/v1/tags [get]
+func (h *TagHandler) Search(c *gin.Context) {
+	var req dto.TagSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	tags, err := h.tagService.Search(c.Request.Context(), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", tags)
+}
+
+// Get godoc
+// @Summary Get a tag with its synonyms
+// @Tags tags
+// @Produce json
+// @Param slug path string true "Tag slug"
+// @Success 200 {object} dto.TagResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/tags/{slug} [get]
+func (h *TagHandler) Get(c *gin.Context) {
+	tag, err := h.tagService.GetBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "", tag)
+}
+
+// GetStories godoc
+// @Summary Get stories with a tag
+// @Tags tags
+// @Produce json
+// @Param slug path string true "Tag slug"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /api/v1/tags/{slug}/stories [get]
+func (h *TagHandler) GetStories(c *gin.Context) {
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	stories, total, err := h.storyService.GetAll(c.Request.Context(), []string{c.Param("slug")}, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(stories, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// AddSynonym godoc
+// @Summary Add a synonym to a tag (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param slug path string true "Tag slug"
+// @Param request body dto.AddTagSynonymRequest true "Synonym"
+// @Success 201 {object} dto.TagResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/tags/{slug}/synonyms [post]
+func (h *TagHandler) AddSynonym(c *gin.Context) {
+	var req dto.AddTagSynonymRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	tag, err := h.tagService.AddSynonym(c.Request.Context(), c.Param("slug"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Synonym added", tag)
+}
+
+// Merge godoc
+// @Summary Merge a tag into another tag (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param slug path string true "Tag slug to merge away"
+// @Param request body dto.MergeTagRequest true "Target tag"
+// @Success 200 {object} dto.TagResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/admin/tags/{slug}/merge [post]
+func (h *TagHandler) Merge(c *gin.Context) {
+	var req dto.MergeTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+
+	tag, err := h.tagService.Merge(c.Request.Context(), c.Param("slug"), &req)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tags merged", tag)
+}
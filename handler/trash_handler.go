@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/middleware"
+	"web-be/service"
+	"web-be/utils"
+)
+
+type TrashHandler struct {
+	trashService *service.TrashService
+}
+
+func NewTrashHandler(trashService *service.TrashService) *TrashHandler {
+	return &TrashHandler{trashService: trashService}
+}
+
+// GetMyTrash godoc
+// @Summary List my deleted stories and chapters
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /api/v1/me/trash [get]
+func (h *TrashHandler) GetMyTrash(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+	h.list(c, &userID)
+}
+
+// GetAll godoc
+// @Summary List everyone's deleted stories and chapters (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Success 200 {object} utils.PaginatedResponse
+// @Router /api/v1/admin/trash [get]
+func (h *TrashHandler) GetAll(c *gin.Context) {
+	h.list(c, nil)
+}
+
+func (h *TrashHandler) list(c *gin.Context, authorID *int) {
+	var pagination dto.PaginationRequest
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		_ = c.Error(utils.BindingError(err))
+		return
+	}
+	pagination.Normalize()
+
+	items, total, err := h.trashService.List(c.Request.Context(), authorID, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := utils.NewPaginatedResponse(items, pagination.Page, pagination.PageSize, total)
+	utils.SuccessResponse(c, http.StatusOK, "", response)
+}
+
+// RestoreStory godoc
+// @Summary Restore a deleted story
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Story ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/me/trash/stories/{id}/restore [post]
+func (h *TrashHandler) RestoreStory(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+	userRole, _ := middleware.GetUserRole(c)
+
+	id, ok := parseTrashIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.RestoreStory(c.Request.Context(), id, userID, userRole); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Story restored", nil)
+}
+
+// RestoreChapter godoc
+// @Summary Restore a deleted chapter
+// @Tags trash
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Chapter ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 400 {object} utils.APIResponse
+// @Router /api/v1/me/trash/chapters/{id}/restore [post]
+func (h *TrashHandler) RestoreChapter(c *gin.Context) {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		_ = c.Error(apperror.Unauthorized("unauthorized", "Unauthorized"))
+		return
+	}
+	userRole, _ := middleware.GetUserRole(c)
+
+	id, ok := parseTrashIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.RestoreChapter(c.Request.Context(), id, userID, userRole); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Chapter restored", nil)
+}
+
+// PurgeStory godoc
+// @Summary Permanently delete a trashed story (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Story ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/admin/trash/stories/{id} [delete]
+func (h *TrashHandler) PurgeStory(c *gin.Context) {
+	id, ok := parseTrashIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.PurgeStory(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Story permanently deleted", nil)
+}
+
+// PurgeChapter godoc
+// @Summary Permanently delete a trashed chapter (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Chapter ID"
+// @Success 200 {object} utils.APIResponse
+// @Failure 404 {object} utils.APIResponse
+// @Router /api/v1/admin/trash/chapters/{id} [delete]
+func (h *TrashHandler) PurgeChapter(c *gin.Context) {
+	id, ok := parseTrashIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.PurgeChapter(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Chapter permanently deleted", nil)
+}
+
+func parseTrashIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		_ = c.Error(apperror.Validation("invalid_id", "Invalid ID"))
+		return 0, false
+	}
+	return id, true
+}
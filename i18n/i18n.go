@@ -0,0 +1,127 @@
+// Package i18n translates the messages the API sends to clients. Messages are
+// keyed by their English text, which is also the format string filled in with
+// any arguments. The English catalog lists every key; each other catalog must
+// translate exactly that set, which Load checks so a missing translation stops
+// the server at startup instead of surfacing as English text in production.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	English    = "en"
+	Vietnamese = "vi"
+)
+
+// Default is used when neither the user nor the request names a supported language
+const Default = English
+
+//go:embed locales/*.json
+var locales embed.FS
+
+var catalogs = map[string]map[string]string{}
+
+// Load reads the embedded catalogs and verifies that every language translates
+// exactly the keys of the English catalog, with the same format verbs
+func Load() error {
+	loaded := make(map[string]map[string]string)
+	for _, lang := range []string{English, Vietnamese} {
+		data, err := locales.ReadFile("locales/" + lang + ".json")
+		if err != nil {
+			return fmt.Errorf("i18n: %w", err)
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return fmt.Errorf("i18n: %s: %w", lang, err)
+		}
+		loaded[lang] = catalog
+	}
+
+	for lang, catalog := range loaded {
+		if err := checkCatalog(loaded[English], catalog); err != nil {
+			return fmt.Errorf("i18n: %s catalog: %w", lang, err)
+		}
+	}
+
+	catalogs = loaded
+	return nil
+}
+
+var formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9]*(?:\.[0-9]+)?[a-zA-Z%]`)
+
+func checkCatalog(reference, catalog map[string]string) error {
+	var problems []string
+	for key := range reference {
+		text, ok := catalog[key]
+		switch {
+		case !ok:
+			problems = append(problems, "missing "+strconv.Quote(key))
+		case strings.Join(formatVerb.FindAllString(text, -1), "") != strings.Join(formatVerb.FindAllString(key, -1), ""):
+			problems = append(problems, "format verbs differ for "+strconv.Quote(key))
+		}
+	}
+	for key := range catalog {
+		if _, ok := reference[key]; !ok {
+			problems = append(problems, "unknown key "+strconv.Quote(key))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
+
+// Supported reports whether lang has a catalog
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// T translates key into lang and fills in args. Keys missing from the
+// catalogs are logged and returned in English.
+func T(lang, key string, args ...any) string {
+	text, ok := catalogs[lang][key]
+	if !ok {
+		if key != "" {
+			slog.Warn("missing translation", "lang", lang, "key", key)
+		}
+		text = key
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Match picks the supported language the Accept-Language header prefers most,
+// or "" when it names none of them
+func Match(acceptLanguage string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if q > bestQ && Supported(base) {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
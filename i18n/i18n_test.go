@@ -0,0 +1,44 @@
+package i18n
+
+import "testing"
+
+// TestCatalogsComplete runs the startup check over the embedded catalogs, so a
+// missing translation fails CI rather than the next deploy
+func TestCatalogsComplete(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckCatalogReportsProblems(t *testing.T) {
+	reference := map[string]string{
+		"Story not found":    "Story not found",
+		"Wait %d seconds":    "Wait %d seconds",
+		"Category not found": "Category not found",
+	}
+	catalog := map[string]string{
+		"Story not found": "Không tìm thấy truyện",
+		"Wait %d seconds": "Vui lòng chờ",
+		"Unused message":  "Thông báo thừa",
+	}
+
+	err := checkCatalog(reference, catalog)
+	if err == nil {
+		t.Fatal("expected incomplete catalog to be rejected")
+	}
+	want := `format verbs differ for "Wait %d seconds"; missing "Category not found"; unknown key "Unused message"`
+	if err.Error() != want {
+		t.Fatalf("checkCatalog error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestEnglishCatalogIsIdentity(t *testing.T) {
+	if err := Load(); err != nil {
+		t.Fatal(err)
+	}
+	for key, text := range catalogs[English] {
+		if key != text {
+			t.Errorf("English text for %q is %q; keys are the English messages", key, text)
+		}
+	}
+}
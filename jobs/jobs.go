@@ -0,0 +1,40 @@
+// Package jobs runs periodic background tasks alongside the HTTP server.
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Task is one run of a background job
+type Task func(ctx context.Context) error
+
+// Every runs task immediately and then once per interval until ctx is
+// cancelled. Errors are logged and the job keeps its schedule.
+func Every(ctx context.Context, name string, interval time.Duration, task Task) {
+	slog.InfoContext(ctx, "background job started", "job", name, "interval", interval.String())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		run(ctx, name, task)
+
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "background job stopped", "job", name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func run(ctx context.Context, name string, task Task) {
+	start := time.Now()
+	if err := task(ctx); err != nil {
+		slog.ErrorContext(ctx, "background job failed", "job", name, "error", err)
+		return
+	}
+	slog.DebugContext(ctx, "background job finished", "job", name, "duration", time.Since(start).String())
+}
@@ -1,20 +1,37 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
+	"web-be/cache"
 	"web-be/config"
 	"web-be/db"
 	"web-be/handler"
+	"web-be/i18n"
+	"web-be/jobs"
+	"web-be/metrics"
+	"web-be/ratelimit"
 	"web-be/repository"
 	"web-be/router"
 	"web-be/service"
+	"web-be/tracing"
 	"web-be/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// migrationsDir holds the .up.sql files applied at startup
+const migrationsDir = "db/migrations"
+
 func main() {
 	// Load config
 	cfg, err := config.LoadConfig()
@@ -26,8 +43,20 @@ func main() {
 	utils.InitLogger(cfg.GinMode)
 	slog.Info("config loaded successfully")
 
+	// Tracing exporter; spans are flushed on shutdown
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracesExporter)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
+	utils.SetupValidator()
+
+	// Load message catalogs; a catalog missing a key stops startup
+	if err := i18n.Load(); err != nil {
+		log.Fatalf("Failed to load translations: %v", err)
+	}
 
 	// Connect to database
 	database, err := db.NewPostgresDB(cfg)
@@ -39,14 +68,27 @@ func main() {
 	slog.Info("connected to database successfully")
 
 	// 🔥 RUN MIGRATIONS
-	if err := db.RunMigrations(database, "db/migrations"); err != nil {
+	if err := db.RunMigrations(database, migrationsDir); err != nil {
 		log.Fatalf("Database migration failed: %v", err)
 	}
 
 	slog.Info("database migrated successfully")
 
+	metrics.RegisterDB(database.DB)
+
 	// Initialize JWT Manager
-	jwtManager := utils.NewJWTManager(cfg.JWTSecret, cfg.JWTExpiryHours)
+	jwtManager, err := utils.NewJWTManager(utils.JWTConfig{
+		Secret:      cfg.LegacyJWTSecret(),
+		KeysDir:     cfg.JWTKeysDir,
+		ActiveKeyID: cfg.JWTActiveKeyID,
+		Issuer:      cfg.JWTIssuer,
+		Audience:    cfg.JWTAudience,
+		LegacyUntil: cfg.JWTLegacyUntil,
+		ExpiryHours: cfg.JWTExpiryHours,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize JWT manager: %v", err)
+	}
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(database)
@@ -55,29 +97,177 @@ func main() {
 	chapterRepo := repository.NewChapterRepository(database)
 	historyRepo := repository.NewReadingHistoryRepository(database)
 	bookmarkRepo := repository.NewBookmarkRepository(database)
+	apiTokenRepo := repository.NewAPITokenRepository(database)
+	sessionRepo := repository.NewSessionRepository(database)
+	auditRepo := repository.NewAdminAuditRepository(database)
+	profileRepo := repository.NewProfileRepository(database)
+	followRepo := repository.NewFollowRepository(database)
+	readingListRepo := repository.NewReadingListRepository(database)
+	progressRepo := repository.NewReadingProgressRepository(database)
+	statsRepo := repository.NewReadingStatsRepository(database)
+	analyticsRepo := repository.NewAnalyticsRepository(database)
+	siteStatsRepo := repository.NewSiteStatsRepository(database)
+	tagRepo := repository.NewTagRepository(database)
+	trashRepo := repository.NewTrashRepository(database)
+	rateLimitRepo := repository.NewRateLimitRepository(database)
+
+	// Cache for hot reads; Redis shares entries and invalidations between replicas
+	var appCache cache.Cache
+	switch cfg.CacheStore {
+	case "memory":
+		appCache = cache.NewLRU(cfg.CacheSize)
+	case "redis":
+		redisCache, err := cache.NewRedis(cfg.RedisURL)
+		if err != nil {
+			log.Fatalf("Failed to set up cache: %v", err)
+		}
+		defer redisCache.Close()
+		pingCtx, cancelPing := context.WithTimeout(context.Background(), 2*time.Second)
+		if err := redisCache.Ping(pingCtx); err != nil {
+			slog.Warn("cache not reachable, reads go to the database until it is", "error", err)
+		}
+		cancelPing()
+		appCache = redisCache
+	default:
+		log.Fatalf("Invalid CACHE_STORE %q: want memory or redis", cfg.CacheStore)
+	}
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, jwtManager)
-	storyService := service.NewStoryService(storyRepo, categoryRepo, historyRepo)
-	chapterService := service.NewChapterService(chapterRepo, storyRepo)
-	bookmarkService := service.NewBookmarkService(bookmarkRepo, storyRepo)
+	authService := service.NewAuthService(userRepo, sessionRepo, jwtManager)
+	storyService := service.NewStoryService(storyRepo, historyRepo, tagRepo, appCache)
+	chapterService := service.NewChapterService(chapterRepo, storyRepo, progressRepo, historyRepo, statsRepo, appCache)
+	readingListService := service.NewReadingListService(readingListRepo, storyRepo)
+	bookmarkService := service.NewBookmarkService(bookmarkRepo, storyRepo, readingListService)
+	apiTokenService := service.NewAPITokenService(apiTokenRepo, userRepo)
+	sessionService := service.NewSessionService(sessionRepo, userRepo, auditRepo)
+	adminUserService := service.NewAdminUserService(userRepo, sessionRepo, auditRepo, jwtManager)
+	profileService := service.NewProfileService(userRepo, profileRepo, storyRepo)
+	followService := service.NewFollowService(followRepo, userRepo, profileRepo)
+	readingStatsService := service.NewReadingStatsService(statsRepo)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, storyRepo, chapterRepo, profileRepo)
+	adminStatsService := service.NewAdminStatsService(siteStatsRepo)
+	tagService := service.NewTagService(tagRepo, appCache)
+	categoryService := service.NewCategoryService(categoryRepo, appCache)
+	trashService := service.NewTrashService(trashRepo, storyRepo, chapterRepo, appCache, cfg.TrashRetentionDays)
+
+	// Rate limiting; the Postgres store shares buckets between replicas
+	rateLimitPolicies, err := ratelimit.ParsePolicies(cfg.RateLimitPolicies)
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_POLICIES: %v", err)
+	}
+	var rateLimitStore ratelimit.Store
+	switch cfg.RateLimitStore {
+	case "memory":
+		rateLimitStore = ratelimit.NewMemoryStore()
+	case "postgres":
+		rateLimitStore = ratelimit.NewPostgresStore(rateLimitRepo)
+	default:
+		log.Fatalf("Invalid RATE_LIMIT_STORE %q: want memory or postgres", cfg.RateLimitStore)
+	}
+	limiter := ratelimit.NewLimiter(rateLimitStore, rateLimitPolicies)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	storyHandler := handler.NewStoryHandler(storyService)
 	chapterHandler := handler.NewChapterHandler(chapterService)
 	bookmarkHandler := handler.NewBookmarkHandler(bookmarkService)
+	apiTokenHandler := handler.NewAPITokenHandler(apiTokenService)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	adminUserHandler := handler.NewAdminUserHandler(adminUserService)
+	profileHandler := handler.NewProfileHandler(profileService)
+	followHandler := handler.NewFollowHandler(followService)
+	readingListHandler := handler.NewReadingListHandler(readingListService)
+	readingStatsHandler := handler.NewReadingStatsHandler(readingStatsService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	adminStatsHandler := handler.NewAdminStatsHandler(adminStatsService)
+	tagHandler := handler.NewTagHandler(tagService, storyService)
+	categoryHandler := handler.NewCategoryHandler(categoryService, storyService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	healthHandler := handler.NewHealthHandler(database, migrationsDir)
+
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobsWG sync.WaitGroup
+	jobsWG.Go(func() {
+		jobs.Every(jobsCtx, "admin-stats-rollup", time.Duration(cfg.StatsRefreshMinutes)*time.Minute, adminStatsService.RefreshRollup)
+	})
+	jobsWG.Go(func() {
+		jobs.Every(jobsCtx, "trash-purge", time.Duration(cfg.TrashPurgeMinutes)*time.Minute, trashService.PurgeExpired)
+	})
+	jobsWG.Go(func() {
+		jobs.Every(jobsCtx, "rate-limit-sweep", time.Minute, rateLimitStore.Sweep)
+	})
 
 	// Setup router
-	r := router.NewRouter(jwtManager, authHandler, storyHandler, chapterHandler, bookmarkHandler)
+	r := router.NewRouter(jwtManager, authHandler, storyHandler, storyService, chapterHandler, bookmarkHandler, apiTokenHandler, apiTokenService, sessionHandler, sessionService, adminUserHandler, profileHandler, followHandler, readingListHandler, readingStatsHandler, analyticsHandler, adminStatsHandler, tagHandler, categoryHandler, trashHandler, healthHandler, limiter)
 	engine := r.Setup()
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	server := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      engine,
+		ReadTimeout:  time.Duration(cfg.ServerReadTimeoutSeconds) * time.Second,
+		WriteTimeout: time.Duration(cfg.ServerWriteTimeoutSeconds) * time.Second,
+		IdleTimeout:  time.Duration(cfg.ServerIdleTimeoutSeconds) * time.Second,
+	}
 
 	// Start server
 	slog.Info("server starting", "port", cfg.Port)
 	slog.Info("Story Reader API ready", "url", "http://localhost:"+cfg.Port+"/api/v1")
 
-	if err := engine.Run(":" + cfg.Port); err != nil {
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	serverErr := make(chan error, 2)
+	go serve(server, serverErr)
+
+	var metricsServer *http.Server
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsServer = &http.Server{
+			Addr:         cfg.MetricsAddr,
+			Handler:      mux,
+			ReadTimeout:  server.ReadTimeout,
+			WriteTimeout: server.WriteTimeout,
+		}
+		slog.Info("metrics listening", "addr", cfg.MetricsAddr)
+		go serve(metricsServer, serverErr)
+	}
+
+	select {
+	case err := <-serverErr:
 		slog.Error("failed to start server", "error", err)
 		log.Fatalf("Failed to start server: %v", err)
+	case <-signalCtx.Done():
+	}
+
+	// Let in-flight requests finish, then stop the background jobs; the
+	// deferred database Close runs last
+	slog.Info("shutting down", "timeout_seconds", cfg.ShutdownTimeoutSeconds)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeoutSeconds)*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("server did not drain in time", "error", err)
+	}
+	if metricsServer != nil {
+		_ = metricsServer.Shutdown(shutdownCtx)
+	}
+
+	stopJobs()
+	jobsWG.Wait()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	slog.Info("server stopped")
+}
+
+// serve runs srv until it is shut down, reporting any other failure on errs
+func serve(srv *http.Server, errs chan<- error) {
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		errs <- err
 	}
 }
@@ -1,49 +1,117 @@
 package middleware
 
 import (
+	"log/slog"
 	"net/http"
 	"strings"
 
+	"web-be/apperror"
+	"web-be/service"
 	"web-be/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
+// passwordChangePath is the one route open to users who must reset their password
+const passwordChangePath = "/api/v1/me/password"
+
+// AuthMiddleware accepts either a JWT or a personal access token. Access tokens
+// are only let through when they carry every scope listed for the route, so
+// routes registered without scopes stay JWT-only.
+// JWTs bound to a session are rejected once that session is revoked; JWTs
+// from before sessions existed only during the legacy window (JWT_LEGACY_UNTIL).
+// While an admin-forced password reset is pending, only the password change
+// endpoint is let through.
+func AuthMiddleware(jwtManager *utils.JWTManager, tokenService *service.APITokenService, sessionService *service.SessionService, scopes ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+			abortWithError(c, apperror.Unauthorized("missing_token", "Authorization header required"))
 			return
 		}
 
 		// Expected format: "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
-			c.Abort()
+			abortWithError(c, apperror.Unauthorized("invalid_token", "Invalid authorization format"))
+			return
+		}
+
+		if utils.IsAPIToken(parts[1]) {
+			user, token, err := tokenService.Authenticate(c.Request.Context(), parts[1])
+			if err != nil {
+				abortWithError(c, apperror.Unauthorized("invalid_token", "Invalid or expired token"))
+				return
+			}
+
+			if len(scopes) == 0 {
+				abortWithError(c, apperror.Forbidden("api_token_not_allowed", "This endpoint does not accept API tokens"))
+				return
+			}
+			for _, scope := range scopes {
+				if !token.HasScope(scope) {
+					abortWithError(c, apperror.Forbidden("missing_scope", "Token is missing required scope: %s").WithArgs(scope))
+					return
+				}
+			}
+			if user.MustResetPassword {
+				abortWithError(c, apperror.Forbidden("password_reset_required", "You must change your password before continuing"))
+				return
+			}
+
+			c.Set("user_id", user.ID)
+			c.Set("username", user.Username)
+			c.Set("role", user.Role)
+			c.Set("api_token_id", token.ID)
+			if user.Language != nil {
+				c.Set("language", *user.Language)
+			}
+			addLogAttrs(c, slog.Int("user_id", user.ID), slog.Int("api_token_id", token.ID))
+
+			c.Next()
 			return
 		}
 
 		claims, err := jwtManager.ValidateToken(parts[1])
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			c.Abort()
+			abortWithError(c, apperror.Unauthorized("invalid_token", "Invalid or expired token"))
+			return
+		}
+
+		mustReset, err := checkTokenHolder(c, jwtManager, sessionService, claims)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		// Support impersonating the user still sees what they would
+		if mustReset && claims.ImpersonatorID == 0 && c.FullPath() != passwordChangePath {
+			abortWithError(c, apperror.Forbidden("password_reset_required", "You must change your password before continuing"))
 			return
 		}
 
+		// Impersonation tokens let support look, never change anything
+		if claims.ImpersonatorID != 0 {
+			if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+				abortWithError(c, apperror.Forbidden("impersonation_read_only", "Impersonation sessions are read-only"))
+				return
+			}
+			markImpersonated(c, sessionService, claims)
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Set("language", claims.Language)
+		addLogAttrs(c, slog.Int("user_id", claims.UserID))
 
 		c.Next()
 	}
 }
 
 // OptionalAuthMiddleware - sets user info if token provided, but doesn't require it
-func OptionalAuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
+func OptionalAuthMiddleware(jwtManager *utils.JWTManager, sessionService *service.SessionService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -63,9 +131,27 @@ func OptionalAuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
 			return
 		}
 
+		// Users with a pending password reset browse public routes anonymously
+		mustReset, err := checkTokenHolder(c, jwtManager, sessionService, claims)
+		if err != nil || (mustReset && claims.ImpersonatorID == 0) {
+			c.Next()
+			return
+		}
+
+		if claims.ImpersonatorID != 0 {
+			if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+				abortWithError(c, apperror.Forbidden("impersonation_read_only", "Impersonation sessions are read-only"))
+				return
+			}
+			markImpersonated(c, sessionService, claims)
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+		c.Set("language", claims.Language)
+		addLogAttrs(c, slog.Int("user_id", claims.UserID))
 
 		c.Next()
 	}
@@ -76,8 +162,7 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role")
 		if !exists {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
-			c.Abort()
+			abortWithError(c, apperror.Forbidden("forbidden", "Access denied"))
 			return
 		}
 
@@ -88,8 +173,7 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
-		c.Abort()
+		abortWithError(c, apperror.Forbidden("forbidden", "Insufficient permissions"))
 	}
 }
 
@@ -103,6 +187,27 @@ func GetUserID(c *gin.Context) (int, bool) {
 	return id, ok
 }
 
+// GetImpersonatorID returns the admin acting as the user, if the request was
+// made with an impersonation token
+func GetImpersonatorID(c *gin.Context) (int, bool) {
+	impersonatorID, exists := c.Get("impersonator_id")
+	if !exists {
+		return 0, false
+	}
+	id, ok := impersonatorID.(int)
+	return id, ok
+}
+
+// GetSessionID helper to get the JWT session ID from context
+func GetSessionID(c *gin.Context) (int, bool) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		return 0, false
+	}
+	id, ok := sessionID.(int)
+	return id, ok
+}
+
 // GetUserRole helper to get user role from context
 func GetUserRole(c *gin.Context) (string, bool) {
 	role, exists := c.Get("role")
@@ -112,3 +217,45 @@ func GetUserRole(c *gin.Context) (string, bool) {
 	r, ok := role.(string)
 	return r, ok
 }
+
+// checkTokenHolder checks that a JWT's session is still active, or for tokens
+// issued before sessions existed, which cannot be revoked, that the legacy
+// window is open and the account is neither banned nor suspended. It reports
+// whether the user must reset their password.
+func checkTokenHolder(c *gin.Context, jwtManager *utils.JWTManager, sessionService *service.SessionService, claims *utils.JWTClaims) (bool, error) {
+	ctx := c.Request.Context()
+	if claims.SessionID != 0 {
+		session, err := sessionService.Validate(ctx, claims.SessionID, claims.UserID)
+		if err != nil {
+			return false, apperror.Unauthorized("session_expired", "Session has been revoked or expired")
+		}
+		return session.MustResetPassword, nil
+	}
+
+	if !jwtManager.LegacyWindowOpen() {
+		return false, apperror.Unauthorized("invalid_token", "Invalid or expired token")
+	}
+	user, err := sessionService.ValidateUnbound(ctx, claims.UserID)
+	if err != nil {
+		return false, apperror.Unauthorized("session_expired", "Session has been revoked or expired")
+	}
+	return user.MustResetPassword, nil
+}
+
+// markImpersonated flags a request made by support as the user and writes it
+// to the admin audit log
+func markImpersonated(c *gin.Context, sessionService *service.SessionService, claims *utils.JWTClaims) {
+	ctx := c.Request.Context()
+	slog.InfoContext(ctx, "impersonated request", "impersonator_id", claims.ImpersonatorID, "user_id", claims.UserID,
+		"method", c.Request.Method, "path", c.Request.URL.Path)
+	sessionService.RecordImpersonatedRequest(ctx, claims.ImpersonatorID, claims.UserID, claims.SessionID,
+		c.Request.Method, c.Request.URL.Path, c.ClientIP())
+	c.Set("impersonator_id", claims.ImpersonatorID)
+	addLogAttrs(c, slog.Int("impersonator_id", claims.ImpersonatorID))
+}
+
+// addLogAttrs stamps attrs on every line logged with the request context from
+// here on, including the access log
+func addLogAttrs(c *gin.Context, attrs ...slog.Attr) {
+	c.Request = c.Request.WithContext(utils.WithLogAttrs(c.Request.Context(), attrs...))
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"web-be/apperror"
+	"web-be/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrorHandler turns the last error a handler attached with c.Error into a
+// response. Domain errors keep their status, code and message; anything else
+// is logged and reported as a generic internal error.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := apperror.From(c.Errors.Last().Err)
+		if err.Kind == apperror.KindInternal {
+			slog.ErrorContext(c.Request.Context(), "request failed", "error", err.Err, "method", c.Request.Method, "path", c.Request.URL.Path)
+			trace.SpanFromContext(c.Request.Context()).RecordError(err.Err)
+		}
+		utils.AppErrorResponse(c, err)
+	}
+}
+
+// abortWithError stops the handler chain and leaves err for ErrorHandler
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
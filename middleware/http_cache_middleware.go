@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPCache adds validators and a Cache-Control policy to successful GET and
+// HEAD responses of a route, and answers conditional requests with 304.
+//
+// The response is buffered so its ETag can be a hash of the body; handlers
+// may set a stronger ETag themselves. Anonymous responses may be cached by
+// shared caches for maxAge; responses to requests carrying credentials are
+// private and revalidated on every use, since they can hold per-reader data
+// such as reading progress.
+//
+// If-Modified-Since is deliberately ignored, as RFC 9110 allows. Story and
+// chapter bodies carry view counts, tag usage and reading progress, none of
+// which has a timestamp, so no Last-Modified would change whenever the body
+// does; clients revalidate with the ETag instead.
+func HTTPCache(maxAge time.Duration) gin.HandlerFunc {
+	publicPolicy := fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = w
+		// Restored on panic too, so Recovery writes to the real response
+		defer func() { c.Writer = w.ResponseWriter }()
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		// Nothing written: the handler left an error for ErrorHandler
+		if !w.written {
+			return
+		}
+
+		header := c.Writer.Header()
+		if w.status == http.StatusOK {
+			header.Add("Vary", "Authorization")
+			if header.Get("Cache-Control") == "" {
+				if c.GetHeader("Authorization") != "" {
+					header.Set("Cache-Control", "private, no-cache")
+				} else {
+					header.Set("Cache-Control", publicPolicy)
+				}
+			}
+
+			etag := header.Get("ETag")
+			if etag == "" {
+				sum := sha256.Sum256(w.body.Bytes())
+				etag = `W/"` + hex.EncodeToString(sum[:16]) + `"`
+				header.Set("ETag", etag)
+			}
+
+			if match := c.GetHeader("If-None-Match"); match != "" && etagMatches(match, etag) {
+				header.Del("Content-Type")
+				header.Del("Content-Length")
+				c.Writer.WriteHeader(http.StatusNotModified)
+				c.Writer.WriteHeaderNow()
+				return
+			}
+		}
+
+		c.Writer.WriteHeader(w.status)
+		if w.body.Len() == 0 || c.Request.Method == http.MethodHead {
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		_, _ = c.Writer.Write(w.body.Bytes())
+	}
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds the status and body back until HTTPCache has decided
+// between the response and a 304. Headers go straight to the real writer.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+	w.written = true
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op: the body can only be sent once it is complete
+func (w *bufferedWriter) Flush() {}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"web-be/apperror"
+	"web-be/metrics"
+	"web-be/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit applies the named policy to the route. Signed-in users are
+// limited by user ID, everyone else by client IP, which honours
+// X-Forwarded-For only from the configured trusted proxies. Place it after
+// the auth middleware so users are recognised.
+//
+// A policy missing from the configuration is logged at startup and leaves the
+// route unlimited.
+//
+// Every limited response carries RateLimit-Limit, RateLimit-Remaining,
+// RateLimit-Reset and RateLimit-Policy; rejected requests also get
+// Retry-After. If the store fails the request is let through, so a database
+// hiccup doesn't take the API down with it.
+func RateLimit(limiter *ratelimit.Limiter, name string) gin.HandlerFunc {
+	policy, ok := limiter.Policy(name)
+	if !ok {
+		slog.Warn("rate limit policy not configured, route is not limited", "policy", name)
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if userID, ok := GetUserID(c); ok {
+			key = "user:" + strconv.Itoa(userID)
+		}
+
+		result, err := limiter.Take(c.Request.Context(), policy, key)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "rate limit check failed, allowing request", "policy", policy.Name, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(int(result.Reset.Seconds())))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Period.Seconds())))
+
+		if !result.Allowed {
+			retryAfter := int(result.RetryAfter.Seconds())
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			metrics.RateLimited.WithLabelValues(policy.Name).Inc()
+			abortWithError(c, apperror.RateLimited("rate_limited", "Too many requests, try again in %d seconds").WithArgs(retryAfter))
+			return
+		}
+
+		c.Next()
+	}
+}
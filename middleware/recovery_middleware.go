@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime/debug"
+
+	"web-be/apperror"
+	"web-be/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panicking handler into a 500 problem response and logs the
+// panic and stack through slog, rather than gin's plain-text writer
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		slog.ErrorContext(c.Request.Context(), "panic recovered", "panic", recovered, "stack", string(debug.Stack()))
+		utils.AppErrorResponse(c, apperror.Internal(fmt.Errorf("panic: %v", recovered)))
+		c.Abort()
+	})
+}
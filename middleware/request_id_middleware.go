@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"log/slog"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID limits IDs accepted from clients or proxies to a safe size
+// and alphabet, since they are echoed back and written to the logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID keeps the X-Request-ID set by a client or load balancer, or
+// assigns a new one, and echoes it on the response. The ID is stored as
+// "request_id" and attached to every log line written with the request context.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = rand.Text()
+		}
+
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		addLogAttrs(c, slog.String("request_id", id))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"web-be/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StorySlugRedirect answers requests addressed to a story's former slug with a
+// permanent redirect to the same path under its current slug. GET and HEAD get
+// 301; other methods get 308 so the method and body are preserved.
+func StorySlugRedirect(storyService *service.StoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		slug := c.Param("slug")
+		if slug == "" {
+			c.Next()
+			return
+		}
+
+		current, err := storyService.ResolveOldSlug(c.Request.Context(), slug)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "failed to resolve story slug", "error", err, "slug", slug)
+			c.Next()
+			return
+		}
+		if current == "" || current == slug {
+			c.Next()
+			return
+		}
+
+		location := strings.Replace(c.Request.URL.Path, "/stories/"+slug, "/stories/"+current, 1)
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+
+		code := http.StatusPermanentRedirect
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		c.Redirect(code, location)
+		c.Abort()
+	}
+}
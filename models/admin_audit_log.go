@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Admin actions recorded in admin_audit_logs
+const (
+	AuditActionSuspend            = "user.suspend"
+	AuditActionUnsuspend          = "user.unsuspend"
+	AuditActionBan                = "user.ban"
+	AuditActionUnban              = "user.unban"
+	AuditActionChangeRole         = "user.change_role"
+	AuditActionForcePasswordReset = "user.force_password_reset"
+	AuditActionImpersonate        = "user.impersonate"
+	// AuditActionImpersonatedRequest is a request made with an impersonation token
+	AuditActionImpersonatedRequest = "user.impersonated_request"
+)
+
+type AdminAuditLog struct {
+	ID           int             `db:"id" json:"id"`
+	ActorID      *int            `db:"actor_id" json:"actor_id,omitempty"`
+	Action       string          `db:"action" json:"action"`
+	TargetUserID *int            `db:"target_user_id" json:"target_user_id,omitempty"`
+	Details      json.RawMessage `db:"details" json:"details"`
+	IPAddress    *string         `db:"ip_address" json:"ip_address,omitempty"`
+	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
+}
+
+// AdminAuditLogWithUsers - for display with actor and target usernames
+type AdminAuditLogWithUsers struct {
+	AdminAuditLog
+	ActorUsername  *string `db:"actor_username" json:"actor_username,omitempty"`
+	TargetUsername *string `db:"target_username" json:"target_username,omitempty"`
+}
@@ -3,9 +3,19 @@ package models
 import "time"
 
 type Category struct {
-	ID          int       `db:"id" json:"id"`
-	Name        string    `db:"name" json:"name"`
-	Slug        string    `db:"slug" json:"slug"`
-	Description *string   `db:"description" json:"description,omitempty"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ID           int       `db:"id" json:"id"`
+	Name         string    `db:"name" json:"name"`
+	Slug         string    `db:"slug" json:"slug"`
+	Description  *string   `db:"description" json:"description,omitempty"`
+	ParentID     *int      `db:"parent_id" json:"parent_id,omitempty"`
+	DisplayOrder int       `db:"display_order" json:"display_order"`
+	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+}
+
+// CategoryNode - a category in the public genre tree. StoryCount covers
+// published stories in the category and its subcategories.
+type CategoryNode struct {
+	Category
+	StoryCount int64          `db:"story_count" json:"story_count"`
+	Children   []CategoryNode `db:"-" json:"children,omitempty"`
 }
@@ -15,6 +15,7 @@ type Chapter struct {
 	PublishedAt   *time.Time `db:"published_at" json:"published_at,omitempty"`
 	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt     *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 }
 
 // ChapterListItem - lighter version for listing
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ChapterCompletePercent is how far into a chapter a reader must get for it to count as read
+const ChapterCompletePercent = 90
+
+// ChapterRead - a user's progress through one chapter
+type ChapterRead struct {
+	UserID          int        `db:"user_id" json:"-"`
+	ChapterID       int        `db:"chapter_id" json:"chapter_id"`
+	StoryID         int        `db:"story_id" json:"story_id"`
+	ParagraphIndex  int        `db:"paragraph_index" json:"paragraph_index"`
+	ScrollOffset    int        `db:"scroll_offset" json:"scroll_offset"`
+	ProgressPercent int        `db:"progress_percent" json:"progress_percent"`
+	FirstReadAt     time.Time  `db:"first_read_at" json:"first_read_at"`
+	LastReadAt      time.Time  `db:"last_read_at" json:"last_read_at"`
+	CompletedAt     *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+func (r *ChapterRead) IsRead() bool {
+	return r.CompletedAt != nil
+}
+
+// ContinueReadingItem - a story in progress and the chapter to open next
+type ContinueReadingItem struct {
+	StoryID           int       `db:"story_id" json:"story_id"`
+	StoryTitle        string    `db:"story_title" json:"story_title"`
+	StorySlug         string    `db:"story_slug" json:"story_slug"`
+	CoverImageURL     *string   `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	LastReadAt        time.Time `db:"last_read_at" json:"last_read_at"`
+	LastChapterNumber *int      `db:"last_chapter_number" json:"last_chapter_number,omitempty"`
+	NextChapterID     int       `db:"next_chapter_id" json:"next_chapter_id"`
+	NextChapterNumber int       `db:"next_chapter_number" json:"next_chapter_number"`
+	NextChapterTitle  string    `db:"next_chapter_title" json:"next_chapter_title"`
+	ParagraphIndex    int       `db:"paragraph_index" json:"paragraph_index"`
+	ScrollOffset      int       `db:"scroll_offset" json:"scroll_offset"`
+	ProgressPercent   int       `db:"progress_percent" json:"progress_percent"`
+	UnreadChapters    int       `db:"unread_chapters" json:"unread_chapters"`
+}
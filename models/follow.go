@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// FollowUser - a follower or followee with basic profile info
+type FollowUser struct {
+	ID         int       `db:"id" json:"id"`
+	Username   string    `db:"username" json:"username"`
+	FullName   *string   `db:"full_name" json:"full_name,omitempty"`
+	AvatarURL  *string   `db:"avatar_url" json:"avatar_url,omitempty"`
+	FollowedAt time.Time `db:"followed_at" json:"followed_at"`
+}
+
+// Feed item types
+const (
+	FeedItemStoryPublished   = "story_published"
+	FeedItemChapterPublished = "chapter_published"
+)
+
+// FeedItem - one entry in a reader's activity feed
+type FeedItem struct {
+	Type          string    `db:"type" json:"type"`
+	ItemID        int       `db:"item_id" json:"-"`
+	OccurredAt    time.Time `db:"occurred_at" json:"occurred_at"`
+	StoryID       int       `db:"story_id" json:"story_id"`
+	StoryTitle    string    `db:"story_title" json:"story_title"`
+	StorySlug     string    `db:"story_slug" json:"story_slug"`
+	CoverImageURL *string   `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	AuthorID      *int      `db:"author_id" json:"author_id,omitempty"`
+	AuthorName    *string   `db:"author_name" json:"author_name,omitempty"`
+	ChapterNumber *int      `db:"chapter_number" json:"chapter_number,omitempty"`
+	ChapterTitle  *string   `db:"chapter_title" json:"chapter_title,omitempty"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Built-in reading lists every user gets
+const (
+	ReadingListReading    = "reading"
+	ReadingListPlanToRead = "plan_to_read"
+	ReadingListCompleted  = "completed"
+)
+
+// DefaultReadingLists maps each built-in list to its display name, in display order
+var DefaultReadingLists = []struct {
+	Key  string
+	Name string
+}{
+	{ReadingListReading, "Đang đọc"},
+	{ReadingListPlanToRead, "Sẽ đọc"},
+	{ReadingListCompleted, "Đã xong"},
+}
+
+type ReadingList struct {
+	ID          int       `db:"id" json:"id"`
+	UserID      int       `db:"user_id" json:"user_id"`
+	Name        string    `db:"name" json:"name"`
+	Description *string   `db:"description" json:"description,omitempty"`
+	ShareCode   string    `db:"share_code" json:"share_code"`
+	IsPublic    bool      `db:"is_public" json:"is_public"`
+	SystemKey   *string   `db:"system_key" json:"system_key,omitempty"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ReadingListSummary - a list with its owner and counts, for display
+type ReadingListSummary struct {
+	ReadingList
+	OwnerUsername string `db:"owner_username" json:"owner_username"`
+	ItemCount     int    `db:"item_count" json:"item_count"`
+	FollowerCount int    `db:"follower_count" json:"follower_count"`
+	ContainsStory *bool  `db:"contains_story" json:"contains_story,omitempty"`
+}
+
+// ReadingListItem - a story in a list, with story info
+type ReadingListItem struct {
+	StoryID       int       `db:"story_id" json:"story_id"`
+	Position      int       `db:"position" json:"position"`
+	AddedAt       time.Time `db:"added_at" json:"added_at"`
+	StoryTitle    string    `db:"story_title" json:"story_title"`
+	StorySlug     string    `db:"story_slug" json:"story_slug"`
+	CoverImageURL *string   `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	AuthorName    *string   `db:"author_name" json:"author_name,omitempty"`
+	TotalChapters int       `db:"total_chapters" json:"total_chapters"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// ReadingWordsPerMinute is the reading speed used to estimate time spent reading
+const ReadingWordsPerMinute = 200
+
+// ReadingDay - chapters and words read on one day
+type ReadingDay struct {
+	Date     time.Time `db:"read_on" json:"date"`
+	Chapters int       `db:"chapters" json:"chapters"`
+	Words    int64     `db:"words" json:"words"`
+}
+
+// ReadingTotals - aggregate reading over a period
+type ReadingTotals struct {
+	Chapters   int   `db:"chapters" json:"chapters"`
+	Words      int64 `db:"words" json:"words"`
+	Stories    int   `db:"stories" json:"stories"`
+	ActiveDays int   `db:"active_days" json:"active_days"`
+}
+
+// CategoryReadCount - how many chapters a user read in a category
+type CategoryReadCount struct {
+	CategoryID int    `db:"category_id" json:"category_id"`
+	Name       string `db:"name" json:"name"`
+	Slug       string `db:"slug" json:"slug"`
+	Chapters   int    `db:"chapters" json:"chapters"`
+}
+
+// StoryReadCount - how many chapters a user read of a story
+type StoryReadCount struct {
+	StoryID       int     `db:"story_id" json:"story_id"`
+	Title         string  `db:"title" json:"title"`
+	Slug          string  `db:"slug" json:"slug"`
+	CoverImageURL *string `db:"cover_image_url" json:"cover_image_url,omitempty"`
+	Chapters      int     `db:"chapters" json:"chapters"`
+	Words         int64   `db:"words" json:"words"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID         int        `db:"id" json:"id"`
+	UserID     int        `db:"user_id" json:"user_id"`
+	UserAgent  *string    `db:"user_agent" json:"user_agent,omitempty"`
+	IPAddress  *string    `db:"ip_address" json:"ip_address,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	LastSeenAt time.Time  `db:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt  time.Time  `db:"expires_at" json:"expires_at"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+	// ImpersonatorID is set when an admin opened this session as the user
+	ImpersonatorID *int `db:"impersonator_id" json:"impersonator_id,omitempty"`
+	// MustResetPassword mirrors the owner's flag; only GetByID loads it
+	MustResetPassword bool `db:"must_reset_password" json:"-"`
+}
+
+// IsActive reports whether the session can still authenticate requests
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// SiteStatsDay - one row of the site_stats_daily rollup. MonthlyActiveUsers
+// counts distinct readers over the 30 days ending on Day.
+type SiteStatsDay struct {
+	Day                time.Time `db:"day" json:"day"`
+	Signups            int       `db:"signups" json:"signups"`
+	ActiveUsers        int       `db:"active_users" json:"active_users"`
+	MonthlyActiveUsers int       `db:"monthly_active_users" json:"monthly_active_users"`
+	NewStories         int       `db:"new_stories" json:"new_stories"`
+	NewChapters        int       `db:"new_chapters" json:"new_chapters"`
+	ChaptersRead       int       `db:"chapters_read" json:"chapters_read"`
+	Views              int64     `db:"views" json:"views"`
+	RefreshedAt        time.Time `db:"refreshed_at" json:"refreshed_at"`
+}
+
+// SiteTotals - live counts of the main entities
+type SiteTotals struct {
+	Users              int64 `db:"users" json:"users"`
+	SuspendedUsers     int64 `db:"suspended_users" json:"suspended_users"`
+	BannedUsers        int64 `db:"banned_users" json:"banned_users"`
+	Stories            int64 `db:"stories" json:"stories"`
+	PublishedStories   int64 `db:"published_stories" json:"published_stories"`
+	UnpublishedStories int64 `db:"unpublished_stories" json:"unpublished_stories"`
+	// PendingModeration counts stories submitted for review and not yet published
+	PendingModeration int64 `db:"pending_moderation" json:"pending_moderation"`
+	Chapters          int64 `db:"chapters" json:"chapters"`
+}
+
+// TopStory - a story ranked by views over a period
+type TopStory struct {
+	StoryID    int     `db:"story_id" json:"story_id"`
+	Title      string  `db:"title" json:"title"`
+	Slug       string  `db:"slug" json:"slug"`
+	AuthorName *string `db:"author_name" json:"author_name,omitempty"`
+	Views      int64   `db:"views" json:"views"`
+	Readers    int     `db:"readers" json:"readers"`
+}
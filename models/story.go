@@ -17,9 +17,14 @@ type Story struct {
 	IsPublished   bool      `db:"is_published" json:"is_published"`
 	CreatedAt     time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+	// Set while the story is in the trash
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+	// Set while the story waits for an admin to publish it
+	SubmittedAt *time.Time `db:"submitted_at" json:"submitted_at,omitempty"`
 
 	// Relations (populated separately)
 	Categories []Category `json:"categories,omitempty"`
+	Tags       []Tag      `json:"tags,omitempty"`
 }
 
 type StoryCategory struct {
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// StoryAnalyticsDay - one day of activity on a story
+type StoryAnalyticsDay struct {
+	Date          time.Time `db:"day" json:"date"`
+	Views         int64     `db:"views" json:"views"`
+	UniqueReaders int       `db:"unique_readers" json:"unique_readers"`
+	NewBookmarks  int       `db:"new_bookmarks" json:"new_bookmarks"`
+	NewFollowers  int       `db:"new_followers" json:"new_followers"`
+}
+
+// ChapterAnalyticsDay - one day of activity on a chapter
+type ChapterAnalyticsDay struct {
+	Date          time.Time `db:"day" json:"date"`
+	Views         int64     `db:"views" json:"views"`
+	UniqueReaders int       `db:"unique_readers" json:"unique_readers"`
+	Completions   int       `db:"completions" json:"completions"`
+}
+
+// ChapterAnalytics - per-chapter totals over a date range. ContinuedReaders
+// counts readers of the chapter who have also opened the next one.
+type ChapterAnalytics struct {
+	ChapterID        int    `db:"chapter_id" json:"chapter_id"`
+	ChapterNumber    int    `db:"chapter_number" json:"chapter_number"`
+	Title            string `db:"title" json:"title"`
+	Views            int64  `db:"views" json:"views"`
+	UniqueReaders    int    `db:"unique_readers" json:"unique_readers"`
+	Completions      int    `db:"completions" json:"completions"`
+	ContinuedReaders int    `db:"continued_readers" json:"continued_readers"`
+	HasNext          bool   `db:"has_next" json:"has_next"`
+}
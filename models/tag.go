@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Limits on author tagging
+const (
+	MaxTagsPerStory = 10
+	MaxTagLength    = 50
+)
+
+type Tag struct {
+	ID          int       `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Slug        string    `db:"slug" json:"slug"`
+	CanonicalID *int      `db:"canonical_id" json:"canonical_id,omitempty"`
+	UsageCount  int       `db:"usage_count" json:"usage_count"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// IsSynonym reports whether the tag stands in for another tag
+func (t *Tag) IsSynonym() bool {
+	return t.CanonicalID != nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+const (
+	TrashTypeStory   = "story"
+	TrashTypeChapter = "chapter"
+)
+
+// TrashItem - a deleted story or chapter awaiting restore or purge
+type TrashItem struct {
+	Type          string    `db:"type" json:"type"`
+	ID            int       `db:"id" json:"id"`
+	StoryID       int       `db:"story_id" json:"story_id"`
+	StoryTitle    string    `db:"story_title" json:"story_title"`
+	StorySlug     string    `db:"story_slug" json:"story_slug"`
+	ChapterNumber *int      `db:"chapter_number" json:"chapter_number,omitempty"`
+	ChapterTitle  *string   `db:"chapter_title" json:"chapter_title,omitempty"`
+	DeletedAt     time.Time `db:"deleted_at" json:"deleted_at"`
+	// When the item is permanently deleted unless restored first
+	PurgeAt time.Time `db:"-" json:"purge_at"`
+}
@@ -3,14 +3,42 @@ package models
 import "time"
 
 type User struct {
-	ID           int       `db:"id" json:"id"`
-	Username     string    `db:"username" json:"username"`
-	Email        string    `db:"email" json:"email"`
-	PasswordHash string    `db:"password_hash" json:"-"`
-	FullName     *string   `db:"full_name" json:"full_name,omitempty"`
-	AvatarURL    *string   `db:"avatar_url" json:"avatar_url,omitempty"`
-	Role         string    `db:"role" json:"role"`
-	IsActive     bool      `db:"is_active" json:"is_active"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	ID                int        `db:"id" json:"id"`
+	Username          string     `db:"username" json:"username"`
+	Email             string     `db:"email" json:"email"`
+	PasswordHash      string     `db:"password_hash" json:"-"`
+	FullName          *string    `db:"full_name" json:"full_name,omitempty"`
+	AvatarURL         *string    `db:"avatar_url" json:"avatar_url,omitempty"`
+	Role              string     `db:"role" json:"role"`
+	IsActive          bool       `db:"is_active" json:"is_active"`
+	SuspendedUntil    *time.Time `db:"suspended_until" json:"suspended_until,omitempty"`
+	SuspensionReason  *string    `db:"suspension_reason" json:"suspension_reason,omitempty"`
+	BanReason         *string    `db:"ban_reason" json:"ban_reason,omitempty"`
+	MustResetPassword bool       `db:"must_reset_password" json:"must_reset_password"`
+	Language          *string    `db:"language" json:"language,omitempty"`
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Account statuses derived from is_active and suspended_until
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusBanned    = "banned"
+)
+
+// IsSuspended reports whether a temporary suspension is in effect
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.SuspendedUntil != nil && now.Before(*u.SuspendedUntil)
+}
+
+// Status returns the account status shown to admins
+func (u *User) Status(now time.Time) string {
+	if !u.IsActive {
+		return UserStatusBanned
+	}
+	if u.IsSuspended(now) {
+		return UserStatusSuspended
+	}
+	return UserStatusActive
 }
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SocialLinks maps a network name (facebook, website, ...) to a URL
+type SocialLinks map[string]string
+
+func (l SocialLinks) Value() (driver.Value, error) {
+	if l == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+func (l *SocialLinks) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*l = SocialLinks{}
+		return nil
+	default:
+		return errors.New("unsupported type for social links")
+	}
+	return json.Unmarshal(data, l)
+}
+
+type UserProfile struct {
+	UserID          int         `db:"user_id" json:"user_id"`
+	Bio             *string     `db:"bio" json:"bio,omitempty"`
+	SocialLinks     SocialLinks `db:"social_links" json:"social_links"`
+	ShowSocialLinks bool        `db:"show_social_links" json:"show_social_links"`
+	ShowJoinDate    bool        `db:"show_join_date" json:"show_join_date"`
+	ShowStats       bool        `db:"show_stats" json:"show_stats"`
+	ShowFollowers   bool        `db:"show_followers" json:"show_followers"`
+	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+// DefaultUserProfile is used for users who never edited their profile
+func DefaultUserProfile(userID int) *UserProfile {
+	return &UserProfile{
+		UserID:          userID,
+		SocialLinks:     SocialLinks{},
+		ShowSocialLinks: true,
+		ShowJoinDate:    true,
+		ShowStats:       true,
+		ShowFollowers:   true,
+	}
+}
+
+// AuthorStats aggregates an author's published stories
+type AuthorStats struct {
+	StoryCount    int     `db:"story_count" json:"story_count"`
+	TotalViews    int64   `db:"total_views" json:"total_views"`
+	TotalChapters int     `db:"total_chapters" json:"total_chapters"`
+	AverageRating float64 `db:"average_rating" json:"average_rating"`
+}
@@ -0,0 +1,37 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+
+	"web-be/repository"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every
+// replica enforces the same limits. It costs one upsert per limited request.
+type PostgresStore struct {
+	repo *repository.RateLimitRepository
+}
+
+func NewPostgresStore(repo *repository.RateLimitRepository) *PostgresStore {
+	return &PostgresStore{repo: repo}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, policy Policy) (Result, error) {
+	allowed, tokens, err := s.repo.Take(ctx, key, float64(policy.Limit), policy.rate())
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(policy, allowed, tokens), nil
+}
+
+func (s *PostgresStore) Sweep(ctx context.Context) error {
+	deleted, err := s.repo.DeleteExpired(ctx)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		slog.DebugContext(ctx, "expired rate limit buckets deleted", "count", deleted)
+	}
+	return nil
+}
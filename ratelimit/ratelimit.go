@@ -0,0 +1,128 @@
+// Package ratelimit implements token-bucket rate limiting. Each named policy
+// allows Limit requests per Period, refilling continuously, so short bursts up
+// to Limit are fine but the sustained rate is capped. Buckets live in a Store:
+// in memory for a single instance, or in Postgres when replicas must share them.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy is a named limit, such as "login" allowing 10 requests per minute
+type Policy struct {
+	Name   string
+	Limit  int
+	Period time.Duration
+}
+
+// rate is the number of tokens the bucket regains per second
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// Result is the outcome of taking a token, with what clients need to back off
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until a token is available; zero when allowed
+	RetryAfter time.Duration
+}
+
+// newResult derives a Result from the tokens left in a bucket
+func newResult(p Policy, allowed bool, tokens float64) Result {
+	rate := p.rate()
+	result := Result{
+		Allowed:   allowed,
+		Limit:     p.Limit,
+		Remaining: max(int(math.Floor(tokens)), 0),
+		Reset:     seconds((float64(p.Limit) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return result
+}
+
+// seconds rounds up to whole seconds, which is what the headers carry
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(max(s, 0))) * time.Second
+}
+
+// Store keeps token buckets
+type Store interface {
+	// Take spends a token from the bucket for key under policy, if one is left
+	Take(ctx context.Context, key string, policy Policy) (Result, error)
+	// Sweep forgets buckets that have refilled completely
+	Sweep(ctx context.Context) error
+}
+
+// Limiter applies the configured policies to a store
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+}
+
+func NewLimiter(store Store, policies []Policy) *Limiter {
+	byName := make(map[string]Policy, len(policies))
+	for _, p := range policies {
+		byName[p.Name] = p
+	}
+	return &Limiter{store: store, policies: byName}
+}
+
+// Policy returns the policy configured under name; routes whose policy is not
+// configured are not limited
+func (l *Limiter) Policy(name string) (Policy, bool) {
+	p, ok := l.policies[name]
+	return p, ok
+}
+
+// Take spends a token for key, such as "user:42" or "ip:203.0.113.7". Each
+// policy has its own buckets.
+func (l *Limiter) Take(ctx context.Context, policy Policy, key string) (Result, error) {
+	return l.store.Take(ctx, policy.Name+":"+key, policy)
+}
+
+// ParsePolicies reads a comma-separated list of name=limit/period entries,
+// such as "login=10/1m,search=60/1m". The period is a Go duration; a bare
+// unit like "m" means one of it.
+func ParsePolicies(spec string) ([]Policy, error) {
+	var policies []Policy
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, rule, ok := strings.Cut(entry, "=")
+		limit, period, ok2 := strings.Cut(rule, "/")
+		if !ok || !ok2 || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("rate limit policy %q: want name=limit/period", entry)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("rate limit policy %q: limit must be a positive integer", entry)
+		}
+
+		period = strings.TrimSpace(period)
+		if period != "" && (period[0] < '0' || period[0] > '9') {
+			period = "1" + period
+		}
+		d, err := time.ParseDuration(period)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("rate limit policy %q: period must be a positive duration", entry)
+		}
+
+		policies = append(policies, Policy{Name: strings.TrimSpace(name), Limit: n, Period: d})
+	}
+	return policies, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"web-be/models"
+)
+
+type AdminAuditRepository struct {
+	db *sqlx.DB
+}
+
+func NewAdminAuditRepository(db *sqlx.DB) *AdminAuditRepository {
+	return &AdminAuditRepository{db: db}
+}
+
+func (r *AdminAuditRepository) Create(ctx context.Context, entry *models.AdminAuditLog) error {
+	query := `
+		INSERT INTO admin_audit_logs (actor_id, action, target_user_id, details, ip_address)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowxContext(ctx, query,
+		entry.ActorID, entry.Action, entry.TargetUserID, entry.Details, entry.IPAddress,
+	).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// List returns audit entries, optionally only those about one target user
+func (r *AdminAuditRepository) List(ctx context.Context, targetUserID *int, limit, offset int) ([]models.AdminAuditLogWithUsers, int64, error) {
+	var logs []models.AdminAuditLogWithUsers
+	var total int64
+
+	countQuery := `SELECT COUNT(*) FROM admin_audit_logs WHERE ($1::int IS NULL OR target_user_id = $1)`
+	err := r.db.GetContext(ctx, &total, countQuery, targetUserID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT l.*, a.username AS actor_username, t.username AS target_username
+		FROM admin_audit_logs l
+		LEFT JOIN users a ON l.actor_id = a.id
+		LEFT JOIN users t ON l.target_user_id = t.id
+		WHERE ($1::int IS NULL OR l.target_user_id = $1)
+		ORDER BY l.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	err = r.db.SelectContext(ctx, &logs, query, targetUserID, limit, offset)
+	return logs, total, err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"web-be/models"
+)
+
+type AnalyticsRepository struct {
+	db *sqlx.DB
+}
+
+func NewAnalyticsRepository(db *sqlx.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// GetStoryDaily returns one row per day between from and to, including days
+// without activity. authorID may be nil for stories without an account.
+func (r *AnalyticsRepository) GetStoryDaily(ctx context.Context, storyID int, authorID *int, from, to time.Time) ([]models.StoryAnalyticsDay, error) {
+	var days []models.StoryAnalyticsDay
+	query := `
+		WITH days AS (
+			SELECT d::date AS day FROM generate_series($2::date, $3::date, interval '1 day') d
+		)
+		SELECT days.day,
+		       COALESCE((SELECT v.views FROM story_views_daily v WHERE v.story_id = $1 AND v.view_date = days.day), 0) AS views,
+		       (SELECT COUNT(DISTINCT e.user_id) FROM reading_events e WHERE e.story_id = $1 AND e.read_on = days.day) AS unique_readers,
+		       (SELECT COUNT(DISTINCT rl.user_id) FROM reading_list_items i
+		        INNER JOIN reading_lists rl ON i.list_id = rl.id
+		        WHERE i.story_id = $1 AND i.added_at::date = days.day) AS new_bookmarks,
+		       (SELECT COUNT(*) FROM user_follows f WHERE f.followee_id = $4 AND f.created_at::date = days.day) AS new_followers
+		FROM days
+		ORDER BY days.day
+	`
+	err := r.db.SelectContext(ctx, &days, query, storyID, from, to, authorID)
+	return days, err
+}
+
+// CountUniqueReaders counts distinct signed-in readers of a story in the range
+func (r *AnalyticsRepository) CountUniqueReaders(ctx context.Context, storyID int, from, to time.Time) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(DISTINCT user_id) FROM reading_events
+		WHERE story_id = $1 AND read_on BETWEEN $2 AND $3
+	`
+	err := r.db.GetContext(ctx, &count, query, storyID, from, to)
+	return count, err
+}
+
+// CountBookmarks counts users who currently keep the story in any reading list
+func (r *AnalyticsRepository) CountBookmarks(ctx context.Context, storyID int) (int64, error) {
+	var count int64
+	query := `
+		SELECT COUNT(DISTINCT rl.user_id) FROM reading_list_items i
+		INNER JOIN reading_lists rl ON i.list_id = rl.id
+		WHERE i.story_id = $1
+	`
+	err := r.db.GetContext(ctx, &count, query, storyID)
+	return count, err
+}
+
+// GetChapterTotals returns per-chapter totals for the range in chapter order
+func (r *AnalyticsRepository) GetChapterTotals(ctx context.Context, storyID int, from, to time.Time) ([]models.ChapterAnalytics, error) {
+	var chapters []models.ChapterAnalytics
+	query := `
+		SELECT c.id AS chapter_id, c.chapter_number, c.title,
+		       COALESCE((SELECT SUM(v.views) FROM chapter_views_daily v
+		                 WHERE v.chapter_id = c.id AND v.view_date BETWEEN $2 AND $3), 0) AS views,
+		       (SELECT COUNT(DISTINCT e.user_id) FROM reading_events e
+		        WHERE e.chapter_id = c.id AND e.read_on BETWEEN $2 AND $3) AS unique_readers,
+		       (SELECT COUNT(*) FROM chapter_reads cr
+		        WHERE cr.chapter_id = c.id AND cr.completed_at::date BETWEEN $2 AND $3) AS completions,
+		       (SELECT COUNT(DISTINCT e.user_id) FROM reading_events e
+		        WHERE e.chapter_id = c.id AND e.read_on BETWEEN $2 AND $3
+		          AND EXISTS (SELECT 1 FROM chapter_reads nr WHERE nr.user_id = e.user_id AND nr.chapter_id = nxt.id)
+		       ) AS continued_readers,
+		       nxt.id IS NOT NULL AS has_next
+		FROM chapters c
+		LEFT JOIN LATERAL (
+			SELECT n.id FROM chapters n
+			WHERE n.story_id = c.story_id AND n.is_published = true AND n.deleted_at IS NULL
+			  AND n.chapter_number > c.chapter_number
+			ORDER BY n.chapter_number
+			LIMIT 1
+		) nxt ON true
+		WHERE c.story_id = $1 AND c.is_published = true AND c.deleted_at IS NULL
+		ORDER BY c.chapter_number
+	`
+	err := r.db.SelectContext(ctx, &chapters, query, storyID, from, to)
+	return chapters, err
+}
+
+// GetChapterDaily returns one row per day between from and to for a chapter
+func (r *AnalyticsRepository) GetChapterDaily(ctx context.Context, chapterID int, from, to time.Time) ([]models.ChapterAnalyticsDay, error) {
+	var days []models.ChapterAnalyticsDay
+	query := `
+		WITH days AS (
+			SELECT d::date AS day FROM generate_series($2::date, $3::date, interval '1 day') d
+		)
+		SELECT days.day,
+		       COALESCE((SELECT v.views FROM chapter_views_daily v WHERE v.chapter_id = $1 AND v.view_date = days.day), 0) AS views,
+		       (SELECT COUNT(DISTINCT e.user_id) FROM reading_events e WHERE e.chapter_id = $1 AND e.read_on = days.day) AS unique_readers,
+		       (SELECT COUNT(*) FROM chapter_reads cr WHERE cr.chapter_id = $1 AND cr.completed_at::date = days.day) AS completions
+		FROM days
+		ORDER BY days.day
+	`
+	err := r.db.SelectContext(ctx, &days, query, chapterID, from, to)
+	return days, err
+}
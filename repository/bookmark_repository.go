@@ -17,30 +17,54 @@ func NewBookmarkRepository(db *sqlx.DB) *BookmarkRepository {
 	return &BookmarkRepository{db: db}
 }
 
+// Create adds the story to the user's "reading" list, which must already exist
 func (r *BookmarkRepository) Create(ctx context.Context, userID, storyID int) error {
-	query := `INSERT INTO bookmarks (user_id, story_id) VALUES ($1, $2) ON CONFLICT (user_id, story_id) DO NOTHING`
+	query := `
+		INSERT INTO reading_list_items (list_id, story_id, position)
+		SELECT rl.id, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM reading_list_items WHERE list_id = rl.id)
+		FROM reading_lists rl
+		WHERE rl.user_id = $1 AND rl.system_key = 'reading'
+		ON CONFLICT (list_id, story_id) DO NOTHING
+	`
 	_, err := r.db.ExecContext(ctx, query, userID, storyID)
 	return err
 }
 
+// Delete removes the story from every list the user owns
 func (r *BookmarkRepository) Delete(ctx context.Context, userID, storyID int) error {
-	query := `DELETE FROM bookmarks WHERE user_id = $1 AND story_id = $2`
+	query := `
+		DELETE FROM reading_list_items
+		WHERE story_id = $2 AND list_id IN (SELECT id FROM reading_lists WHERE user_id = $1)
+	`
 	_, err := r.db.ExecContext(ctx, query, userID, storyID)
 	return err
 }
 
+// Exists reports whether the story is in any of the user's lists
 func (r *BookmarkRepository) Exists(ctx context.Context, userID, storyID int) (bool, error) {
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM bookmarks WHERE user_id = $1 AND story_id = $2)`
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM reading_list_items i
+			INNER JOIN reading_lists rl ON i.list_id = rl.id
+			WHERE rl.user_id = $1 AND i.story_id = $2
+		)
+	`
 	err := r.db.GetContext(ctx, &exists, query, userID, storyID)
 	return exists, err
 }
 
+// GetByUser returns each story the user keeps in any list once
 func (r *BookmarkRepository) GetByUser(ctx context.Context, userID, limit, offset int) ([]models.BookmarkWithStory, int64, error) {
 	var bookmarks []models.BookmarkWithStory
 	var total int64
 
-	countQuery := `SELECT COUNT(*) FROM bookmarks WHERE user_id = $1`
+	countQuery := `
+		SELECT COUNT(DISTINCT i.story_id) FROM reading_list_items i
+		INNER JOIN reading_lists rl ON i.list_id = rl.id
+		INNER JOIN stories s ON i.story_id = s.id
+		WHERE rl.user_id = $1 AND s.deleted_at IS NULL
+	`
 	err := r.db.GetContext(ctx, &total, countQuery, userID)
 	if err != nil {
 		return nil, 0, err
@@ -51,9 +75,15 @@ func (r *BookmarkRepository) GetByUser(ctx context.Context, userID, limit, offse
 		       s.title AS story_title, s.slug AS story_slug, 
 		       s.cover_image_url, s.author_name,
 		       s.total_chapters, s.total_views
-		FROM bookmarks b
+		FROM (
+			SELECT MIN(i.id) AS id, rl.user_id, i.story_id, MIN(i.added_at) AS created_at
+			FROM reading_list_items i
+			INNER JOIN reading_lists rl ON i.list_id = rl.id
+			WHERE rl.user_id = $1
+			GROUP BY rl.user_id, i.story_id
+		) b
 		INNER JOIN stories s ON b.story_id = s.id
-		WHERE b.user_id = $1
+		WHERE s.deleted_at IS NULL
 		ORDER BY b.created_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -63,7 +93,11 @@ func (r *BookmarkRepository) GetByUser(ctx context.Context, userID, limit, offse
 
 func (r *BookmarkRepository) CountByStory(ctx context.Context, storyID int) (int64, error) {
 	var count int64
-	query := `SELECT COUNT(*) FROM bookmarks WHERE story_id = $1`
+	query := `
+		SELECT COUNT(DISTINCT rl.user_id) FROM reading_list_items i
+		INNER JOIN reading_lists rl ON i.list_id = rl.id
+		WHERE i.story_id = $1
+	`
 	err := r.db.GetContext(ctx, &count, query, storyID)
 	return count, err
 }
@@ -71,7 +105,7 @@ func (r *BookmarkRepository) CountByStory(ctx context.Context, storyID int) (int
 // GetStoryByID - helper to validate story exists
 func (r *BookmarkRepository) GetStoryByID(ctx context.Context, storyID int) (bool, error) {
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM stories WHERE id = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM stories WHERE id = $1 AND deleted_at IS NULL)`
 	err := r.db.GetContext(ctx, &exists, query, storyID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"web-be/models"
 )
 
@@ -18,12 +19,12 @@ func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
 
 func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
 	query := `
-		INSERT INTO categories (name, slug, description)
-		VALUES ($1, $2, $3)
+		INSERT INTO categories (name, slug, description, parent_id, display_order)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at
 	`
 	return r.db.QueryRowxContext(ctx, query,
-		category.Name, category.Slug, category.Description,
+		category.Name, category.Slug, category.Description, category.ParentID, category.DisplayOrder,
 	).Scan(&category.ID, &category.CreatedAt)
 }
 
@@ -53,25 +54,186 @@ func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*model
 	return &category, nil
 }
 
+// GetByOldSlug finds the category a renamed or merged slug now points to
+func (r *CategoryRepository) GetByOldSlug(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	query := `
+		SELECT c.* FROM categories c
+		INNER JOIN category_slug_redirects rd ON c.id = rd.category_id
+		WHERE rd.old_slug = $1
+	`
+	err := r.db.GetContext(ctx, &category, query, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
 func (r *CategoryRepository) GetAll(ctx context.Context) ([]models.Category, error) {
 	var categories []models.Category
-	query := `SELECT * FROM categories ORDER BY name`
+	query := `SELECT * FROM categories ORDER BY display_order, name`
+	err := r.db.SelectContext(ctx, &categories, query)
+	return categories, err
+}
+
+// GetAllWithCounts returns every category in display order with the number of
+// published stories in it or any of its subcategories
+func (r *CategoryRepository) GetAllWithCounts(ctx context.Context) ([]models.CategoryNode, error) {
+	var categories []models.CategoryNode
+	query := `
+		SELECT c.*, (
+			SELECT COUNT(DISTINCT s.id) FROM stories s
+			INNER JOIN story_categories sc ON s.id = sc.story_id
+			INNER JOIN categories sub ON sc.category_id = sub.id
+			WHERE s.is_published = true AND s.deleted_at IS NULL AND (sub.id = c.id OR sub.parent_id = c.id)
+		) AS story_count
+		FROM categories c
+		ORDER BY c.display_order, c.name
+	`
 	err := r.db.SelectContext(ctx, &categories, query)
 	return categories, err
 }
 
-func (r *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+// GetStorySlugs returns the slugs of stories filed directly under the category
+func (r *CategoryRepository) GetStorySlugs(ctx context.Context, id int) ([]string, error) {
+	var slugs []string
+	query := `
+		SELECT s.slug FROM stories s
+		INNER JOIN story_categories sc ON s.id = sc.story_id
+		WHERE sc.category_id = $1
+	`
+	err := r.db.SelectContext(ctx, &slugs, query, id)
+	return slugs, err
+}
+
+func (r *CategoryRepository) CountChildren(ctx context.Context, id int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM categories WHERE parent_id = $1`
+	err := r.db.GetContext(ctx, &count, query, id)
+	return count, err
+}
+
+// Update saves a category; when the slug changed the old one is kept as a redirect
+func (r *CategoryRepository) Update(ctx context.Context, category *models.Category, oldSlug string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE categories 
-		SET name = $1, slug = $2, description = $3
-		WHERE id = $4
+		SET name = $1, slug = $2, description = $3, parent_id = $4, display_order = $5
+		WHERE id = $6
 	`
-	_, err := r.db.ExecContext(ctx, query, category.Name, category.Slug, category.Description, category.ID)
+	_, err = tx.ExecContext(ctx, query,
+		category.Name, category.Slug, category.Description, category.ParentID, category.DisplayOrder, category.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	if oldSlug != category.Slug {
+		// The new slug may be a former one being reused
+		if _, err := tx.ExecContext(ctx, `DELETE FROM category_slug_redirects WHERE old_slug = $1`, category.Slug); err != nil {
+			return err
+		}
+		if err := addRedirect(ctx, tx, oldSlug, category.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Reorder sets display_order to each category's position in ids
+func (r *CategoryRepository) Reorder(ctx context.Context, ids []int) error {
+	query := `
+		UPDATE categories c SET display_order = o.position
+		FROM unnest($1::int[]) WITH ORDINALITY AS o(id, position)
+		WHERE c.id = o.id
+	`
+	_, err := r.db.ExecContext(ctx, query, pq.Array(ids))
 	return err
 }
 
-func (r *CategoryRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM categories WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
+// Delete removes a category. When reassignTo is set its stories are moved to
+// that category first; subcategories become top-level.
+func (r *CategoryRepository) Delete(ctx context.Context, id int, reassignTo *int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if reassignTo != nil {
+		if err := moveStories(ctx, tx, id, *reassignTo); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Merge folds source into target: stories and subcategories move over and
+// source's slugs redirect to target
+func (r *CategoryRepository) Merge(ctx context.Context, source *models.Category, targetID int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := moveStories(ctx, tx, source.ID, targetID); err != nil {
+		return err
+	}
+
+	statements := []string{
+		`UPDATE categories SET parent_id = $2 WHERE parent_id = $1`,
+		`UPDATE category_slug_redirects SET category_id = $2 WHERE category_id = $1`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt, source.ID, targetID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM categories WHERE id = $1`, source.ID); err != nil {
+		return err
+	}
+	if err := addRedirect(ctx, tx, source.Slug, targetID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func moveStories(ctx context.Context, tx *sqlx.Tx, fromID, toID int) error {
+	query := `
+		INSERT INTO story_categories (story_id, category_id)
+		SELECT story_id, $2 FROM story_categories WHERE category_id = $1
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, fromID, toID); err != nil {
+		return err
+	}
+	_, err := tx.ExecContext(ctx, `DELETE FROM story_categories WHERE category_id = $1`, fromID)
+	return err
+}
+
+func addRedirect(ctx context.Context, tx *sqlx.Tx, oldSlug string, categoryID int) error {
+	query := `
+		INSERT INTO category_slug_redirects (old_slug, category_id)
+		VALUES ($1, $2)
+		ON CONFLICT (old_slug) DO UPDATE SET category_id = EXCLUDED.category_id
+	`
+	_, err := tx.ExecContext(ctx, query, oldSlug, categoryID)
 	return err
 }
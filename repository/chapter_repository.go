@@ -30,7 +30,7 @@ func (r *ChapterRepository) Create(ctx context.Context, chapter *models.Chapter)
 
 func (r *ChapterRepository) GetByID(ctx context.Context, id int) (*models.Chapter, error) {
 	var chapter models.Chapter
-	query := `SELECT * FROM chapters WHERE id = $1`
+	query := `SELECT * FROM chapters WHERE id = $1 AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &chapter, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -43,7 +43,7 @@ func (r *ChapterRepository) GetByID(ctx context.Context, id int) (*models.Chapte
 
 func (r *ChapterRepository) GetByStoryAndNumber(ctx context.Context, storyID, chapterNumber int) (*models.Chapter, error) {
 	var chapter models.Chapter
-	query := `SELECT * FROM chapters WHERE story_id = $1 AND chapter_number = $2`
+	query := `SELECT * FROM chapters WHERE story_id = $1 AND chapter_number = $2 AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &chapter, query, storyID, chapterNumber)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -56,7 +56,7 @@ func (r *ChapterRepository) GetByStoryAndNumber(ctx context.Context, storyID, ch
 
 func (r *ChapterRepository) GetByStoryAndSlug(ctx context.Context, storyID int, slug string) (*models.Chapter, error) {
 	var chapter models.Chapter
-	query := `SELECT * FROM chapters WHERE story_id = $1 AND slug = $2`
+	query := `SELECT * FROM chapters WHERE story_id = $1 AND slug = $2 AND deleted_at IS NULL ORDER BY chapter_number LIMIT 1`
 	err := r.db.GetContext(ctx, &chapter, query, storyID, slug)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -67,11 +67,40 @@ func (r *ChapterRepository) GetByStoryAndSlug(ctx context.Context, storyID int,
 	return &chapter, nil
 }
 
+// GetByOldSlug finds the chapter that used to have slug within a story
+func (r *ChapterRepository) GetByOldSlug(ctx context.Context, storyID int, slug string) (*models.Chapter, error) {
+	var chapter models.Chapter
+	query := `
+		SELECT c.* FROM chapters c
+		INNER JOIN chapter_slug_history h ON c.id = h.chapter_id
+		WHERE h.story_id = $1 AND h.old_slug = $2 AND c.deleted_at IS NULL
+	`
+	err := r.db.GetContext(ctx, &chapter, query, storyID, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+// SlugTaken reports whether slug is used, now or formerly, by another chapter of the story
+func (r *ChapterRepository) SlugTaken(ctx context.Context, storyID int, slug string, chapterID int) (bool, error) {
+	var taken bool
+	query := `
+		SELECT EXISTS (SELECT 1 FROM chapters WHERE story_id = $1 AND slug = $2 AND id <> $3)
+		    OR EXISTS (SELECT 1 FROM chapter_slug_history WHERE story_id = $1 AND old_slug = $2 AND chapter_id <> $3)
+	`
+	err := r.db.GetContext(ctx, &taken, query, storyID, slug, chapterID)
+	return taken, err
+}
+
 func (r *ChapterRepository) GetListByStory(ctx context.Context, storyID int, limit, offset int) ([]models.ChapterListItem, int64, error) {
 	var chapters []models.ChapterListItem
 	var total int64
 
-	countQuery := `SELECT COUNT(*) FROM chapters WHERE story_id = $1 AND is_published = true`
+	countQuery := `SELECT COUNT(*) FROM chapters WHERE story_id = $1 AND is_published = true AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &total, countQuery, storyID)
 	if err != nil {
 		return nil, 0, err
@@ -80,7 +109,7 @@ func (r *ChapterRepository) GetListByStory(ctx context.Context, storyID int, lim
 	query := `
 		SELECT id, story_id, chapter_number, title, slug, word_count, views, is_published, published_at, created_at
 		FROM chapters 
-		WHERE story_id = $1 AND is_published = true
+		WHERE story_id = $1 AND is_published = true AND deleted_at IS NULL
 		ORDER BY chapter_number ASC
 		LIMIT $2 OFFSET $3
 	`
@@ -93,45 +122,101 @@ func (r *ChapterRepository) GetAllByStory(ctx context.Context, storyID int) ([]m
 	query := `
 		SELECT id, story_id, chapter_number, title, slug, word_count, views, is_published, published_at, created_at
 		FROM chapters 
-		WHERE story_id = $1 AND is_published = true
+		WHERE story_id = $1 AND is_published = true AND deleted_at IS NULL
 		ORDER BY chapter_number ASC
 	`
 	err := r.db.SelectContext(ctx, &chapters, query, storyID)
 	return chapters, err
 }
 
-func (r *ChapterRepository) Update(ctx context.Context, chapter *models.Chapter) error {
+// Update saves a chapter; when the slug changed the old one is kept in the slug history
+func (r *ChapterRepository) Update(ctx context.Context, chapter *models.Chapter, oldSlug string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE chapters 
 		SET title = $1, slug = $2, content = $3, word_count = $4, 
 		    is_published = $5, published_at = $6, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $7
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		chapter.Title, chapter.Slug, chapter.Content, chapter.WordCount,
 		chapter.IsPublished, chapter.PublishedAt, chapter.ID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if oldSlug != chapter.Slug {
+		query = `DELETE FROM chapter_slug_history WHERE story_id = $1 AND old_slug = $2`
+		if _, err := tx.ExecContext(ctx, query, chapter.StoryID, chapter.Slug); err != nil {
+			return err
+		}
+		query = `
+			INSERT INTO chapter_slug_history (story_id, old_slug, chapter_id) VALUES ($1, $2, $3)
+			ON CONFLICT (story_id, old_slug) DO UPDATE SET chapter_id = EXCLUDED.chapter_id, created_at = CURRENT_TIMESTAMP
+		`
+		if _, err := tx.ExecContext(ctx, query, chapter.StoryID, oldSlug, chapter.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
+// Delete moves a chapter to the trash; it stays restorable until purged
 func (r *ChapterRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM chapters WHERE id = $1`
+	query := `UPDATE chapters SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
+// IncrementViews bumps the lifetime counter and today's row in chapter_views_daily
 func (r *ChapterRepository) IncrementViews(ctx context.Context, id int) error {
-	query := `UPDATE chapters SET views = views + 1 WHERE id = $1`
+	query := `
+		WITH updated AS (
+			UPDATE chapters SET views = views + 1 WHERE id = $1 RETURNING id, story_id
+		)
+		INSERT INTO chapter_views_daily (chapter_id, story_id, view_date, views)
+		SELECT id, story_id, CURRENT_DATE, 1 FROM updated
+		ON CONFLICT (chapter_id, view_date) DO UPDATE SET views = chapter_views_daily.views + 1
+	`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
+// GetNavNeighbourhood returns the numbers of the chapters whose prev/next links
+// may name chapter n: every chapter from the nearest published one before n
+// to the nearest published one after it, running to the start or end of the
+// story where there is none. Chapter n's own state doesn't move these bounds.
+func (r *ChapterRepository) GetNavNeighbourhood(ctx context.Context, storyID, n int) ([]int, error) {
+	var numbers []int
+	query := `
+		SELECT chapter_number FROM chapters
+		WHERE story_id = $1
+		  AND chapter_number >= COALESCE((
+			SELECT MAX(chapter_number) FROM chapters
+			WHERE story_id = $1 AND chapter_number < $2 AND is_published = true AND deleted_at IS NULL
+		  ), chapter_number)
+		  AND chapter_number <= COALESCE((
+			SELECT MIN(chapter_number) FROM chapters
+			WHERE story_id = $1 AND chapter_number > $2 AND is_published = true AND deleted_at IS NULL
+		  ), chapter_number)
+	`
+	err := r.db.SelectContext(ctx, &numbers, query, storyID, n)
+	return numbers, err
+}
+
 func (r *ChapterRepository) GetNextChapter(ctx context.Context, storyID, currentNumber int) (*models.ChapterListItem, error) {
 	var chapter models.ChapterListItem
 	query := `
 		SELECT id, story_id, chapter_number, title, slug, word_count, views, is_published, published_at, created_at
 		FROM chapters 
-		WHERE story_id = $1 AND chapter_number > $2 AND is_published = true
+		WHERE story_id = $1 AND chapter_number > $2 AND is_published = true AND deleted_at IS NULL
 		ORDER BY chapter_number ASC
 		LIMIT 1
 	`
@@ -150,7 +235,7 @@ func (r *ChapterRepository) GetPrevChapter(ctx context.Context, storyID, current
 	query := `
 		SELECT id, story_id, chapter_number, title, slug, word_count, views, is_published, published_at, created_at
 		FROM chapters 
-		WHERE story_id = $1 AND chapter_number < $2 AND is_published = true
+		WHERE story_id = $1 AND chapter_number < $2 AND is_published = true AND deleted_at IS NULL
 		ORDER BY chapter_number DESC
 		LIMIT 1
 	`
@@ -164,6 +249,8 @@ func (r *ChapterRepository) GetPrevChapter(ctx context.Context, storyID, current
 	return &chapter, nil
 }
 
+// GetMaxChapterNumber includes trashed chapters so a restore never collides
+// with a chapter created in the meantime
 func (r *ChapterRepository) GetMaxChapterNumber(ctx context.Context, storyID int) (int, error) {
 	var maxNum sql.NullInt64
 	query := `SELECT MAX(chapter_number) FROM chapters WHERE story_id = $1`
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"web-be/models"
+)
+
+type FollowRepository struct {
+	db *sqlx.DB
+}
+
+func NewFollowRepository(db *sqlx.DB) *FollowRepository {
+	return &FollowRepository{db: db}
+}
+
+func (r *FollowRepository) Create(ctx context.Context, followerID, followeeID int) error {
+	query := `INSERT INTO user_follows (follower_id, followee_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	return err
+}
+
+func (r *FollowRepository) Delete(ctx context.Context, followerID, followeeID int) error {
+	query := `DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2`
+	_, err := r.db.ExecContext(ctx, query, followerID, followeeID)
+	return err
+}
+
+func (r *FollowRepository) Exists(ctx context.Context, followerID, followeeID int) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM user_follows WHERE follower_id = $1 AND followee_id = $2)`
+	err := r.db.GetContext(ctx, &exists, query, followerID, followeeID)
+	return exists, err
+}
+
+func (r *FollowRepository) GetFollowers(ctx context.Context, userID, limit, offset int) ([]models.FollowUser, int64, error) {
+	var users []models.FollowUser
+	var total int64
+
+	countQuery := `SELECT COUNT(*) FROM user_follows WHERE followee_id = $1`
+	err := r.db.GetContext(ctx, &total, countQuery, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT u.id, u.username, u.full_name, u.avatar_url, f.created_at AS followed_at
+		FROM user_follows f
+		INNER JOIN users u ON f.follower_id = u.id
+		WHERE f.followee_id = $1 AND u.is_active = true
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	err = r.db.SelectContext(ctx, &users, query, userID, limit, offset)
+	return users, total, err
+}
+
+func (r *FollowRepository) GetFollowing(ctx context.Context, userID, limit, offset int) ([]models.FollowUser, int64, error) {
+	var users []models.FollowUser
+	var total int64
+
+	countQuery := `SELECT COUNT(*) FROM user_follows WHERE follower_id = $1`
+	err := r.db.GetContext(ctx, &total, countQuery, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT u.id, u.username, u.full_name, u.avatar_url, f.created_at AS followed_at
+		FROM user_follows f
+		INNER JOIN users u ON f.followee_id = u.id
+		WHERE f.follower_id = $1 AND u.is_active = true
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	err = r.db.SelectContext(ctx, &users, query, userID, limit, offset)
+	return users, total, err
+}
+
+// FeedCursor marks the last item of the previous page: items strictly older
+// (by occurred_at, then type, then id) are returned next
+type FeedCursor struct {
+	OccurredAt time.Time
+	Type       string
+	ItemID     int
+}
+
+// GetFeed assembles the feed at read time (fan-out-on-read): new stories by
+// followed authors plus new chapters of stories by followed authors or
+// bookmarked by the reader. Nothing is written per follower when an author
+// publishes, so authors with many followers cost nothing extra.
+func (r *FollowRepository) GetFeed(ctx context.Context, userID int, cursor *FeedCursor, limit int) ([]models.FeedItem, error) {
+	var items []models.FeedItem
+
+	var cursorAt *time.Time
+	var cursorType *string
+	var cursorID *int
+	if cursor != nil {
+		cursorAt, cursorType, cursorID = &cursor.OccurredAt, &cursor.Type, &cursor.ItemID
+	}
+
+	query := `
+		WITH followed_authors AS (
+			SELECT followee_id FROM user_follows WHERE follower_id = $1
+		),
+		feed_stories AS (
+			SELECT id FROM stories WHERE author_id IN (SELECT followee_id FROM followed_authors)
+			UNION
+			SELECT i.story_id FROM reading_list_items i
+			INNER JOIN reading_lists rl ON i.list_id = rl.id
+			WHERE rl.user_id = $1
+		),
+		items AS (
+			SELECT 'story_published' AS type, s.id AS item_id, s.created_at AS occurred_at,
+			       s.id AS story_id, NULL::int AS chapter_number, NULL::varchar AS chapter_title
+			FROM stories s
+			WHERE s.author_id IN (SELECT followee_id FROM followed_authors) AND s.is_published = true
+			UNION ALL
+			SELECT 'chapter_published', c.id, c.published_at,
+			       c.story_id, c.chapter_number, c.title
+			FROM chapters c
+			WHERE c.story_id IN (SELECT id FROM feed_stories) AND c.is_published = true AND c.published_at IS NOT NULL
+			  AND c.deleted_at IS NULL
+		)
+		SELECT i.type, i.item_id, i.occurred_at, i.story_id, i.chapter_number, i.chapter_title,
+		       s.title AS story_title, s.slug AS story_slug, s.cover_image_url, s.author_id, s.author_name
+		FROM items i
+		INNER JOIN stories s ON i.story_id = s.id
+		WHERE s.is_published = true AND s.deleted_at IS NULL
+		  AND ($2::timestamp IS NULL OR (i.occurred_at, i.type, i.item_id) < ($2::timestamp, $3::text, $4::int))
+		ORDER BY i.occurred_at DESC, i.type DESC, i.item_id DESC
+		LIMIT $5
+	`
+	err := r.db.SelectContext(ctx, &items, query, userID, cursorAt, cursorType, cursorID, limit)
+	return items, err
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"web-be/models"
+)
+
+type ProfileRepository struct {
+	db *sqlx.DB
+}
+
+func NewProfileRepository(db *sqlx.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+// GetByUserID returns the user's profile, or the defaults if none was saved
+func (r *ProfileRepository) GetByUserID(ctx context.Context, userID int) (*models.UserProfile, error) {
+	var profile models.UserProfile
+	query := `SELECT * FROM user_profiles WHERE user_id = $1`
+	err := r.db.GetContext(ctx, &profile, query, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.DefaultUserProfile(userID), nil
+		}
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *ProfileRepository) Upsert(ctx context.Context, profile *models.UserProfile) error {
+	query := `
+		INSERT INTO user_profiles (user_id, bio, social_links, show_social_links, show_join_date, show_stats, show_followers)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id)
+		DO UPDATE SET bio = $2, social_links = $3, show_social_links = $4, show_join_date = $5,
+		              show_stats = $6, show_followers = $7, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+	return r.db.QueryRowxContext(ctx, query,
+		profile.UserID, profile.Bio, profile.SocialLinks, profile.ShowSocialLinks,
+		profile.ShowJoinDate, profile.ShowStats, profile.ShowFollowers,
+	).Scan(&profile.UpdatedAt)
+}
+
+func (r *ProfileRepository) GetAuthorStats(ctx context.Context, authorID int) (*models.AuthorStats, error) {
+	var stats models.AuthorStats
+	query := `
+		SELECT COUNT(*) AS story_count,
+		       COALESCE(SUM(total_views), 0) AS total_views,
+		       COALESCE(SUM(total_chapters), 0) AS total_chapters,
+		       COALESCE(AVG(NULLIF(rating, 0)), 0) AS average_rating
+		FROM stories
+		WHERE author_id = $1 AND is_published = true AND deleted_at IS NULL
+	`
+	err := r.db.GetContext(ctx, &stats, query, authorID)
+	return &stats, err
+}
+
+func (r *ProfileRepository) CountFollowers(ctx context.Context, userID int) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM user_follows WHERE followee_id = $1`
+	err := r.db.GetContext(ctx, &count, query, userID)
+	return count, err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RateLimitRepository struct {
+	db *sqlx.DB
+}
+
+func NewRateLimitRepository(db *sqlx.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// Take refills the bucket for key at ratePerSecond, up to capacity, and spends
+// a token if a whole one is available. The read-modify-write is one upsert,
+// so concurrent requests on different replicas cannot overspend a bucket.
+// It returns whether the request was allowed and the tokens left.
+func (r *RateLimitRepository) Take(ctx context.Context, key string, capacity, ratePerSecond float64) (bool, float64, error) {
+	var result struct {
+		Allowed bool    `db:"allowed"`
+		Tokens  float64 `db:"tokens"`
+	}
+	query := `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at, expires_at)
+		VALUES ($1, $2::float8 - 1, TRUE, CURRENT_TIMESTAMP,
+			CURRENT_TIMESTAMP + make_interval(secs => 1 / $3::float8))
+		ON CONFLICT (key) DO UPDATE SET (tokens, allowed, updated_at, expires_at) = (
+			SELECT refilled - spent, spent = 1, CURRENT_TIMESTAMP,
+				CURRENT_TIMESTAMP + make_interval(secs => ($2::float8 - refilled + spent) / $3::float8)
+			FROM (
+				SELECT refilled, CASE WHEN refilled >= 1 THEN 1 ELSE 0 END AS spent
+				FROM (
+					SELECT LEAST($2::float8,
+						b.tokens + EXTRACT(EPOCH FROM CURRENT_TIMESTAMP - b.updated_at)::float8 * $3::float8) AS refilled
+				) AS bucket
+			) AS take
+		)
+		RETURNING allowed, tokens
+	`
+	err := r.db.GetContext(ctx, &result, query, key, capacity, ratePerSecond)
+	return result.Allowed, result.Tokens, err
+}
+
+// DeleteExpired removes buckets that have refilled completely; a missing
+// bucket behaves exactly like a full one
+func (r *RateLimitRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM rate_limit_buckets WHERE expires_at < CURRENT_TIMESTAMP`
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
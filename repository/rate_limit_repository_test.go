@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// newTestDB connects to the database named by TEST_DATABASE_URL, a throwaway
+// Postgres these integration tests may write to, and skips without one
+func newTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func applyMigration(t *testing.T, db *sqlx.DB, name string) {
+	t.Helper()
+	sql, err := os.ReadFile("../db/migrations/" + name + ".up.sql")
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	if _, err := db.Exec(string(sql)); err != nil {
+		t.Fatalf("apply migration %s: %v", name, err)
+	}
+}
+
+func newRateLimitTestRepo(t *testing.T) (*RateLimitRepository, *sqlx.DB, string) {
+	db := newTestDB(t)
+	applyMigration(t, db, "023_create_rate_limit_buckets")
+
+	key := fmt.Sprintf("test:%s:%d", t.Name(), time.Now().UnixNano())
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM rate_limit_buckets WHERE key = $1`, key)
+	})
+	return NewRateLimitRepository(db), db, key
+}
+
+// rewind moves the bucket's last update into the past, as if d had elapsed
+func rewind(t *testing.T, db *sqlx.DB, key string, d time.Duration) {
+	t.Helper()
+	query := `UPDATE rate_limit_buckets SET updated_at = updated_at - make_interval(secs => $2) WHERE key = $1`
+	if _, err := db.Exec(query, key, d.Seconds()); err != nil {
+		t.Fatalf("rewind bucket: %v", err)
+	}
+}
+
+func take(t *testing.T, repo *RateLimitRepository, key string, capacity, rate float64) (bool, float64) {
+	t.Helper()
+	allowed, tokens, err := repo.Take(context.Background(), key, capacity, rate)
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	return allowed, tokens
+}
+
+func TestRateLimitTakeSpendsUntilEmpty(t *testing.T) {
+	repo, _, key := newRateLimitTestRepo(t)
+
+	// A slow refill keeps elapsed time between statements negligible
+	const capacity, rate = 3, 0.001
+	for i := 0; i < capacity; i++ {
+		allowed, tokens := take(t, repo, key, capacity, rate)
+		if !allowed {
+			t.Fatalf("take %d rejected", i+1)
+		}
+		if want := float64(capacity - i - 1); math.Abs(tokens-want) > 0.01 {
+			t.Fatalf("take %d left %.3f tokens, want %.0f", i+1, tokens, want)
+		}
+	}
+
+	allowed, tokens := take(t, repo, key, capacity, rate)
+	if allowed {
+		t.Fatal("take from an empty bucket was allowed")
+	}
+	if tokens >= 1 || tokens < 0 {
+		t.Fatalf("empty bucket holds %.3f tokens", tokens)
+	}
+}
+
+func TestRateLimitTakeRefills(t *testing.T) {
+	repo, db, key := newRateLimitTestRepo(t)
+
+	const capacity, rate = 1, 0.5
+	if allowed, _ := take(t, repo, key, capacity, rate); !allowed {
+		t.Fatal("first take rejected")
+	}
+	if allowed, _ := take(t, repo, key, capacity, rate); allowed {
+		t.Fatal("take before refill was allowed")
+	}
+
+	// Two seconds at half a token per second buys one token back
+	rewind(t, db, key, 2*time.Second)
+	if allowed, _ := take(t, repo, key, capacity, rate); !allowed {
+		t.Fatal("take after refill rejected")
+	}
+}
+
+func TestRateLimitTakeRefillStopsAtCapacity(t *testing.T) {
+	repo, db, key := newRateLimitTestRepo(t)
+
+	const capacity, rate = 3, 1
+	take(t, repo, key, capacity, rate)
+
+	rewind(t, db, key, time.Hour)
+	allowed, tokens := take(t, repo, key, capacity, rate)
+	if !allowed {
+		t.Fatal("take from a refilled bucket rejected")
+	}
+	if math.Abs(tokens-(capacity-1)) > 0.01 {
+		t.Fatalf("refilled bucket left %.3f tokens, want %d", tokens, capacity-1)
+	}
+}
+
+func TestRateLimitDeleteExpired(t *testing.T) {
+	repo, db, key := newRateLimitTestRepo(t)
+
+	take(t, repo, key, 2, 1)
+	if _, err := db.Exec(`UPDATE rate_limit_buckets SET expires_at = CURRENT_TIMESTAMP - INTERVAL '1 second' WHERE key = $1`, key); err != nil {
+		t.Fatalf("expire bucket: %v", err)
+	}
+
+	if _, err := repo.DeleteExpired(context.Background()); err != nil {
+		t.Fatalf("DeleteExpired: %v", err)
+	}
+	var count int
+	if err := db.Get(&count, `SELECT COUNT(*) FROM rate_limit_buckets WHERE key = $1`, key); err != nil {
+		t.Fatalf("count buckets: %v", err)
+	}
+	if count != 0 {
+		t.Fatal("expired bucket was not deleted")
+	}
+}
@@ -46,7 +46,11 @@ func (r *ReadingHistoryRepository) GetByUser(ctx context.Context, userID, limit,
 	var histories []models.ReadingHistoryWithDetails
 	var total int64
 
-	countQuery := `SELECT COUNT(*) FROM reading_history WHERE user_id = $1`
+	countQuery := `
+		SELECT COUNT(*) FROM reading_history rh
+		INNER JOIN stories s ON rh.story_id = s.id
+		WHERE rh.user_id = $1 AND s.deleted_at IS NULL
+	`
 	err := r.db.GetContext(ctx, &total, countQuery, userID)
 	if err != nil {
 		return nil, 0, err
@@ -59,8 +63,8 @@ func (r *ReadingHistoryRepository) GetByUser(ctx context.Context, userID, limit,
 			c.chapter_number, c.title as chapter_title
 		FROM reading_history rh
 		INNER JOIN stories s ON rh.story_id = s.id
-		LEFT JOIN chapters c ON rh.last_chapter_id = c.id
-		WHERE rh.user_id = $1
+		LEFT JOIN chapters c ON rh.last_chapter_id = c.id AND c.deleted_at IS NULL
+		WHERE rh.user_id = $1 AND s.deleted_at IS NULL
 		ORDER BY rh.last_read_at DESC
 		LIMIT $2 OFFSET $3
 	`
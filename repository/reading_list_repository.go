@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"web-be/models"
+)
+
+type ReadingListRepository struct {
+	db *sqlx.DB
+}
+
+func NewReadingListRepository(db *sqlx.DB) *ReadingListRepository {
+	return &ReadingListRepository{db: db}
+}
+
+// readingListSummaryColumns selects a list with owner name and counts; joined as rl and u.
+// Items whose story is in the trash are left out of item_count, as they are of the list.
+const readingListSummaryColumns = `
+	rl.*, u.username AS owner_username,
+	(SELECT COUNT(*) FROM reading_list_items i
+	 INNER JOIN stories s ON s.id = i.story_id AND s.deleted_at IS NULL
+	 WHERE i.list_id = rl.id) AS item_count,
+	(SELECT COUNT(*) FROM reading_list_follows f WHERE f.list_id = rl.id) AS follower_count
+`
+
+func (r *ReadingListRepository) Create(ctx context.Context, list *models.ReadingList) error {
+	query := `
+		INSERT INTO reading_lists (user_id, name, description, share_code, is_public, system_key)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRowxContext(ctx, query,
+		list.UserID, list.Name, list.Description, list.ShareCode, list.IsPublic, list.SystemKey,
+	).Scan(&list.ID, &list.CreatedAt, &list.UpdatedAt)
+}
+
+// CreateIfMissing inserts a built-in list unless the user already has it
+func (r *ReadingListRepository) CreateIfMissing(ctx context.Context, list *models.ReadingList) error {
+	query := `
+		INSERT INTO reading_lists (user_id, name, share_code, system_key)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, system_key) WHERE system_key IS NOT NULL DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, list.UserID, list.Name, list.ShareCode, list.SystemKey)
+	return err
+}
+
+func (r *ReadingListRepository) CountSystemLists(ctx context.Context, userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM reading_lists WHERE user_id = $1 AND system_key IS NOT NULL`
+	err := r.db.GetContext(ctx, &count, query, userID)
+	return count, err
+}
+
+func (r *ReadingListRepository) GetByShareCode(ctx context.Context, code string) (*models.ReadingListSummary, error) {
+	var list models.ReadingListSummary
+	query := `
+		SELECT ` + readingListSummaryColumns + `
+		FROM reading_lists rl
+		INNER JOIN users u ON rl.user_id = u.id
+		WHERE rl.share_code = $1
+	`
+	err := r.db.GetContext(ctx, &list, query, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetByUser returns the user's lists, built-in ones first. When storyID is
+// non-zero each list reports whether it contains that story.
+func (r *ReadingListRepository) GetByUser(ctx context.Context, userID, storyID int) ([]models.ReadingListSummary, error) {
+	var lists []models.ReadingListSummary
+	query := `
+		SELECT ` + readingListSummaryColumns + `,
+		       CASE WHEN $2 = 0 THEN NULL
+		            ELSE EXISTS(SELECT 1 FROM reading_list_items i WHERE i.list_id = rl.id AND i.story_id = $2)
+		       END AS contains_story
+		FROM reading_lists rl
+		INNER JOIN users u ON rl.user_id = u.id
+		WHERE rl.user_id = $1
+		ORDER BY rl.system_key IS NULL, rl.id
+	`
+	err := r.db.SelectContext(ctx, &lists, query, userID, storyID)
+	return lists, err
+}
+
+func (r *ReadingListRepository) GetFollowedByUser(ctx context.Context, userID, limit, offset int) ([]models.ReadingListSummary, int64, error) {
+	var lists []models.ReadingListSummary
+	var total int64
+
+	countQuery := `
+		SELECT COUNT(*) FROM reading_list_follows f
+		INNER JOIN reading_lists rl ON f.list_id = rl.id
+		WHERE f.user_id = $1 AND rl.is_public = true
+	`
+	err := r.db.GetContext(ctx, &total, countQuery, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT ` + readingListSummaryColumns + `
+		FROM reading_list_follows lf
+		INNER JOIN reading_lists rl ON lf.list_id = rl.id
+		INNER JOIN users u ON rl.user_id = u.id
+		WHERE lf.user_id = $1 AND rl.is_public = true
+		ORDER BY lf.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	err = r.db.SelectContext(ctx, &lists, query, userID, limit, offset)
+	return lists, total, err
+}
+
+func (r *ReadingListRepository) Update(ctx context.Context, list *models.ReadingList) error {
+	query := `
+		UPDATE reading_lists 
+		SET name = $1, description = $2, is_public = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`
+	_, err := r.db.ExecContext(ctx, query, list.Name, list.Description, list.IsPublic, list.ID)
+	return err
+}
+
+func (r *ReadingListRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM reading_lists WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *ReadingListRepository) GetItems(ctx context.Context, listID, limit, offset int) ([]models.ReadingListItem, int64, error) {
+	var items []models.ReadingListItem
+	var total int64
+
+	countQuery := `
+		SELECT COUNT(*) FROM reading_list_items i
+		INNER JOIN stories s ON i.story_id = s.id
+		WHERE i.list_id = $1 AND s.deleted_at IS NULL
+	`
+	err := r.db.GetContext(ctx, &total, countQuery, listID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT i.story_id, i.position, i.added_at,
+		       s.title AS story_title, s.slug AS story_slug,
+		       s.cover_image_url, s.author_name, s.total_chapters
+		FROM reading_list_items i
+		INNER JOIN stories s ON i.story_id = s.id
+		WHERE i.list_id = $1 AND s.deleted_at IS NULL
+		ORDER BY i.position, i.added_at
+		LIMIT $2 OFFSET $3
+	`
+	err = r.db.SelectContext(ctx, &items, query, listID, limit, offset)
+	return items, total, err
+}
+
+// AddItem appends a story to the end of a list; adding it twice is a no-op
+func (r *ReadingListRepository) AddItem(ctx context.Context, listID, storyID int) error {
+	query := `
+		INSERT INTO reading_list_items (list_id, story_id, position)
+		VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM reading_list_items WHERE list_id = $1))
+		ON CONFLICT (list_id, story_id) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, listID, storyID)
+	return err
+}
+
+func (r *ReadingListRepository) RemoveItem(ctx context.Context, listID, storyID int) error {
+	query := `DELETE FROM reading_list_items WHERE list_id = $1 AND story_id = $2`
+	_, err := r.db.ExecContext(ctx, query, listID, storyID)
+	return err
+}
+
+// ReorderItems sets positions to match storyIDs; stories not listed keep
+// their relative order after the given ones
+func (r *ReadingListRepository) ReorderItems(ctx context.Context, listID int, storyIDs []int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, storyID := range storyIDs {
+		_, err = tx.ExecContext(ctx, `UPDATE reading_list_items SET position = $1 WHERE list_id = $2 AND story_id = $3`, i+1, listID, storyID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE reading_list_items i
+		SET position = $2 + ranked.rn
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position, added_at) AS rn
+			FROM reading_list_items
+			WHERE list_id = $1 AND NOT (story_id = ANY($3))
+		) ranked
+		WHERE i.id = ranked.id
+	`, listID, len(storyIDs), pq.Array(storyIDs))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ReadingListRepository) Follow(ctx context.Context, userID, listID int) error {
+	query := `INSERT INTO reading_list_follows (user_id, list_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, userID, listID)
+	return err
+}
+
+func (r *ReadingListRepository) Unfollow(ctx context.Context, userID, listID int) error {
+	query := `DELETE FROM reading_list_follows WHERE user_id = $1 AND list_id = $2`
+	_, err := r.db.ExecContext(ctx, query, userID, listID)
+	return err
+}
+
+func (r *ReadingListRepository) IsFollowing(ctx context.Context, userID, listID int) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS(SELECT 1 FROM reading_list_follows WHERE user_id = $1 AND list_id = $2)`
+	err := r.db.GetContext(ctx, &exists, query, userID, listID)
+	return exists, err
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"web-be/models"
+)
+
+type ReadingProgressRepository struct {
+	db *sqlx.DB
+}
+
+func NewReadingProgressRepository(db *sqlx.DB) *ReadingProgressRepository {
+	return &ReadingProgressRepository{db: db}
+}
+
+// RecordOpen notes that the user opened a chapter and returns the saved
+// position so the reader can resume where they left off
+func (r *ReadingProgressRepository) RecordOpen(ctx context.Context, userID, storyID, chapterID int) (*models.ChapterRead, error) {
+	var read models.ChapterRead
+	query := `
+		INSERT INTO chapter_reads (user_id, chapter_id, story_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, chapter_id)
+		DO UPDATE SET last_read_at = CURRENT_TIMESTAMP
+		RETURNING *
+	`
+	err := r.db.GetContext(ctx, &read, query, userID, chapterID, storyID)
+	if err != nil {
+		return nil, err
+	}
+	return &read, nil
+}
+
+// SavePosition stores the in-chapter position; once a chapter is completed it
+// stays read even if the reader scrolls back up
+func (r *ReadingProgressRepository) SavePosition(ctx context.Context, read *models.ChapterRead) error {
+	query := `
+		INSERT INTO chapter_reads (user_id, chapter_id, story_id, paragraph_index, scroll_offset, progress_percent, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $6 >= $7 THEN CURRENT_TIMESTAMP END)
+		ON CONFLICT (user_id, chapter_id)
+		DO UPDATE SET paragraph_index = $4, scroll_offset = $5, progress_percent = $6,
+		              last_read_at = CURRENT_TIMESTAMP,
+		              completed_at = COALESCE(chapter_reads.completed_at, EXCLUDED.completed_at)
+		RETURNING first_read_at, last_read_at, completed_at
+	`
+	return r.db.QueryRowxContext(ctx, query,
+		read.UserID, read.ChapterID, read.StoryID, read.ParagraphIndex, read.ScrollOffset,
+		read.ProgressPercent, models.ChapterCompletePercent,
+	).Scan(&read.FirstReadAt, &read.LastReadAt, &read.CompletedAt)
+}
+
+// GetByChapters returns the user's progress for the given chapters, keyed by chapter ID
+func (r *ReadingProgressRepository) GetByChapters(ctx context.Context, userID int, chapterIDs []int) (map[int]models.ChapterRead, error) {
+	reads := make(map[int]models.ChapterRead)
+	if len(chapterIDs) == 0 {
+		return reads, nil
+	}
+
+	var rows []models.ChapterRead
+	query := `SELECT * FROM chapter_reads WHERE user_id = $1 AND chapter_id = ANY($2)`
+	err := r.db.SelectContext(ctx, &rows, query, userID, pq.Array(chapterIDs))
+	if err != nil {
+		return nil, err
+	}
+	for _, read := range rows {
+		reads[read.ChapterID] = read
+	}
+	return reads, nil
+}
+
+// nextUnreadChapter picks, per reading_history row rh, the first published
+// chapter from the last one read onwards that is not completed yet
+const nextUnreadChapter = `
+	SELECT c.id, c.chapter_number, c.title
+	FROM chapters c
+	WHERE c.story_id = rh.story_id AND c.is_published = true AND c.deleted_at IS NULL
+	  AND c.chapter_number >= COALESCE(lc.chapter_number, 0)
+	  AND NOT EXISTS (
+		SELECT 1 FROM chapter_reads r
+		WHERE r.user_id = rh.user_id AND r.chapter_id = c.id AND r.completed_at IS NOT NULL
+	  )
+	ORDER BY c.chapter_number
+	LIMIT 1
+`
+
+// GetContinueReading lists stories the user has started that still have
+// something to read, most recently read first
+func (r *ReadingProgressRepository) GetContinueReading(ctx context.Context, userID, limit, offset int) ([]models.ContinueReadingItem, int64, error) {
+	var items []models.ContinueReadingItem
+	var total int64
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM reading_history rh
+		INNER JOIN stories s ON rh.story_id = s.id
+		LEFT JOIN chapters lc ON rh.last_chapter_id = lc.id AND lc.deleted_at IS NULL
+		WHERE rh.user_id = $1 AND s.deleted_at IS NULL AND EXISTS (` + nextUnreadChapter + `)
+	`
+	err := r.db.GetContext(ctx, &total, countQuery, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT rh.story_id, s.title AS story_title, s.slug AS story_slug, s.cover_image_url, rh.last_read_at,
+		       lc.chapter_number AS last_chapter_number,
+		       nc.id AS next_chapter_id, nc.chapter_number AS next_chapter_number, nc.title AS next_chapter_title,
+		       COALESCE(cr.paragraph_index, 0) AS paragraph_index,
+		       COALESCE(cr.scroll_offset, 0) AS scroll_offset,
+		       COALESCE(cr.progress_percent, 0) AS progress_percent,
+		       (SELECT COUNT(*) FROM chapters c
+		        WHERE c.story_id = rh.story_id AND c.is_published = true AND c.deleted_at IS NULL
+		          AND NOT EXISTS (
+			        SELECT 1 FROM chapter_reads r
+			        WHERE r.user_id = rh.user_id AND r.chapter_id = c.id AND r.completed_at IS NOT NULL
+		          )
+		       ) AS unread_chapters
+		FROM reading_history rh
+		INNER JOIN stories s ON rh.story_id = s.id
+		LEFT JOIN chapters lc ON rh.last_chapter_id = lc.id AND lc.deleted_at IS NULL
+		CROSS JOIN LATERAL (` + nextUnreadChapter + `) nc
+		LEFT JOIN chapter_reads cr ON cr.user_id = rh.user_id AND cr.chapter_id = nc.id
+		WHERE rh.user_id = $1 AND s.deleted_at IS NULL
+		ORDER BY rh.last_read_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	err = r.db.SelectContext(ctx, &items, query, userID, limit, offset)
+	return items, total, err
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"web-be/models"
+)
+
+type ReadingStatsRepository struct {
+	db *sqlx.DB
+}
+
+func NewReadingStatsRepository(db *sqlx.DB) *ReadingStatsRepository {
+	return &ReadingStatsRepository{db: db}
+}
+
+// RecordEvent logs that the user read a chapter today; repeat reads on the
+// same day are counted once
+func (r *ReadingStatsRepository) RecordEvent(ctx context.Context, userID, storyID, chapterID, wordCount int) error {
+	query := `
+		INSERT INTO reading_events (user_id, story_id, chapter_id, word_count)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, chapter_id, read_on) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, storyID, chapterID, wordCount)
+	return err
+}
+
+// GetTotals aggregates reading between from and to (inclusive dates)
+func (r *ReadingStatsRepository) GetTotals(ctx context.Context, userID int, from, to time.Time) (*models.ReadingTotals, error) {
+	var totals models.ReadingTotals
+	query := `
+		SELECT COUNT(*) AS chapters,
+		       COALESCE(SUM(word_count), 0) AS words,
+		       COUNT(DISTINCT story_id) AS stories,
+		       COUNT(DISTINCT read_on) AS active_days
+		FROM reading_events
+		WHERE user_id = $1 AND read_on BETWEEN $2 AND $3
+	`
+	err := r.db.GetContext(ctx, &totals, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// GetDaily returns one row per day with reading between from and to
+func (r *ReadingStatsRepository) GetDaily(ctx context.Context, userID int, from, to time.Time) ([]models.ReadingDay, error) {
+	var days []models.ReadingDay
+	query := `
+		SELECT read_on, COUNT(*) AS chapters, COALESCE(SUM(word_count), 0) AS words
+		FROM reading_events
+		WHERE user_id = $1 AND read_on BETWEEN $2 AND $3
+		GROUP BY read_on
+		ORDER BY read_on
+	`
+	err := r.db.SelectContext(ctx, &days, query, userID, from, to)
+	return days, err
+}
+
+// GetActiveDates returns every distinct day the user read anything up to
+// and including to, newest first
+func (r *ReadingStatsRepository) GetActiveDates(ctx context.Context, userID int, to time.Time) ([]time.Time, error) {
+	var dates []time.Time
+	query := `
+		SELECT DISTINCT read_on FROM reading_events
+		WHERE user_id = $1 AND read_on <= $2
+		ORDER BY read_on DESC
+	`
+	err := r.db.SelectContext(ctx, &dates, query, userID, to)
+	return dates, err
+}
+
+func (r *ReadingStatsRepository) GetTopCategories(ctx context.Context, userID int, from, to time.Time, limit int) ([]models.CategoryReadCount, error) {
+	var categories []models.CategoryReadCount
+	query := `
+		SELECT c.id AS category_id, c.name, c.slug, COUNT(*) AS chapters
+		FROM reading_events e
+		INNER JOIN story_categories sc ON e.story_id = sc.story_id
+		INNER JOIN categories c ON sc.category_id = c.id
+		WHERE e.user_id = $1 AND e.read_on BETWEEN $2 AND $3
+		GROUP BY c.id, c.name, c.slug
+		ORDER BY chapters DESC, c.name
+		LIMIT $4
+	`
+	err := r.db.SelectContext(ctx, &categories, query, userID, from, to, limit)
+	return categories, err
+}
+
+func (r *ReadingStatsRepository) GetTopStories(ctx context.Context, userID int, from, to time.Time, limit int) ([]models.StoryReadCount, error) {
+	var stories []models.StoryReadCount
+	query := `
+		SELECT s.id AS story_id, s.title, s.slug, s.cover_image_url,
+		       COUNT(*) AS chapters, COALESCE(SUM(e.word_count), 0) AS words
+		FROM reading_events e
+		INNER JOIN stories s ON e.story_id = s.id
+		WHERE e.user_id = $1 AND e.read_on BETWEEN $2 AND $3 AND s.deleted_at IS NULL
+		GROUP BY s.id, s.title, s.slug, s.cover_image_url
+		ORDER BY chapters DESC, s.title
+		LIMIT $4
+	`
+	err := r.db.SelectContext(ctx, &stories, query, userID, from, to, limit)
+	return stories, err
+}
+
+// CountStoriesStarted counts stories whose first read by the user falls in the range
+func (r *ReadingStatsRepository) CountStoriesStarted(ctx context.Context, userID int, from, to time.Time) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM (
+			SELECT story_id FROM reading_events
+			WHERE user_id = $1
+			GROUP BY story_id
+			HAVING MIN(read_on) BETWEEN $2 AND $3
+		) started
+	`
+	err := r.db.GetContext(ctx, &count, query, userID, from, to)
+	return count, err
+}
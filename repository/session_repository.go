@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"web-be/models"
+)
+
+type SessionRepository struct {
+	db *sqlx.DB
+}
+
+func NewSessionRepository(db *sqlx.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO user_sessions (user_id, user_agent, ip_address, expires_at, impersonator_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, last_seen_at
+	`
+	return r.db.QueryRowxContext(ctx, query,
+		session.UserID, session.UserAgent, session.IPAddress, session.ExpiresAt, session.ImpersonatorID,
+	).Scan(&session.ID, &session.CreatedAt, &session.LastSeenAt)
+}
+
+// GetByID loads a session along with whether its owner must reset their password
+func (r *SessionRepository) GetByID(ctx context.Context, id int) (*models.Session, error) {
+	var session models.Session
+	query := `
+		SELECT s.*, u.must_reset_password FROM user_sessions s
+		INNER JOIN users u ON u.id = s.user_id
+		WHERE s.id = $1
+	`
+	err := r.db.GetContext(ctx, &session, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) GetActiveByUser(ctx context.Context, userID int) ([]models.Session, error) {
+	var sessions []models.Session
+	query := `
+		SELECT * FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY last_seen_at DESC
+	`
+	err := r.db.SelectContext(ctx, &sessions, query, userID)
+	return sessions, err
+}
+
+// TouchLastSeen bumps last_seen_at, at most once a minute to limit writes
+func (r *SessionRepository) TouchLastSeen(ctx context.Context, id int) error {
+	query := `
+		UPDATE user_sessions SET last_seen_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND last_seen_at < CURRENT_TIMESTAMP - INTERVAL '1 minute'
+	`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// Revoke revokes one of the user's sessions; returns false if none matched
+func (r *SessionRepository) Revoke(ctx context.Context, id, userID int) (bool, error) {
+	query := `UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	return rows > 0, err
+}
+
+// RevokeAllExcept revokes every active session of the user other than keepID
+func (r *SessionRepository) RevokeAllExcept(ctx context.Context, userID, keepID int) (int64, error) {
+	query := `UPDATE user_sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, userID, keepID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
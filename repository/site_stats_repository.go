@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"web-be/models"
+)
+
+type SiteStatsRepository struct {
+	db *sqlx.DB
+}
+
+func NewSiteStatsRepository(db *sqlx.DB) *SiteStatsRepository {
+	return &SiteStatsRepository{db: db}
+}
+
+// Refresh recomputes the rollup rows for every day between from and to
+func (r *SiteStatsRepository) Refresh(ctx context.Context, from, to time.Time) error {
+	query := `
+		INSERT INTO site_stats_daily (day, signups, active_users, monthly_active_users, new_stories, new_chapters, chapters_read, views, refreshed_at)
+		SELECT d::date,
+		       (SELECT COUNT(*) FROM users WHERE created_at >= d AND created_at < d + interval '1 day'),
+		       (SELECT COUNT(DISTINCT user_id) FROM reading_events WHERE read_on = d::date),
+		       (SELECT COUNT(DISTINCT user_id) FROM reading_events WHERE read_on BETWEEN d::date - 29 AND d::date),
+		       (SELECT COUNT(*) FROM stories WHERE created_at >= d AND created_at < d + interval '1 day' AND deleted_at IS NULL),
+		       (SELECT COUNT(*) FROM chapters WHERE created_at >= d AND created_at < d + interval '1 day' AND deleted_at IS NULL),
+		       (SELECT COUNT(*) FROM reading_events WHERE read_on = d::date),
+		       (SELECT COALESCE(SUM(views), 0) FROM story_views_daily WHERE view_date = d::date),
+		       CURRENT_TIMESTAMP
+		FROM generate_series($1::date, $2::date, interval '1 day') d
+		ON CONFLICT (day) DO UPDATE SET
+		    signups = EXCLUDED.signups,
+		    active_users = EXCLUDED.active_users,
+		    monthly_active_users = EXCLUDED.monthly_active_users,
+		    new_stories = EXCLUDED.new_stories,
+		    new_chapters = EXCLUDED.new_chapters,
+		    chapters_read = EXCLUDED.chapters_read,
+		    views = EXCLUDED.views,
+		    refreshed_at = EXCLUDED.refreshed_at
+	`
+	_, err := r.db.ExecContext(ctx, query, from, to)
+	return err
+}
+
+// GetLatestDay returns the most recent day in the rollup, or nil when it is empty
+func (r *SiteStatsRepository) GetLatestDay(ctx context.Context) (*time.Time, error) {
+	var day sql.NullTime
+	query := `SELECT MAX(day) FROM site_stats_daily`
+	err := r.db.GetContext(ctx, &day, query)
+	if err != nil || !day.Valid {
+		return nil, err
+	}
+	return &day.Time, nil
+}
+
+func (r *SiteStatsRepository) GetDaily(ctx context.Context, from, to time.Time) ([]models.SiteStatsDay, error) {
+	var days []models.SiteStatsDay
+	query := `SELECT * FROM site_stats_daily WHERE day BETWEEN $1 AND $2 ORDER BY day`
+	err := r.db.SelectContext(ctx, &days, query, from, to)
+	return days, err
+}
+
+// GetTotals counts users, stories and chapters as of now
+func (r *SiteStatsRepository) GetTotals(ctx context.Context) (*models.SiteTotals, error) {
+	var totals models.SiteTotals
+	query := `
+		SELECT (SELECT COUNT(*) FROM users) AS users,
+		       (SELECT COUNT(*) FROM users WHERE is_active = true AND suspended_until > CURRENT_TIMESTAMP) AS suspended_users,
+		       (SELECT COUNT(*) FROM users WHERE is_active = false) AS banned_users,
+		       (SELECT COUNT(*) FROM stories WHERE deleted_at IS NULL) AS stories,
+		       (SELECT COUNT(*) FROM stories WHERE is_published = true AND deleted_at IS NULL) AS published_stories,
+		       (SELECT COUNT(*) FROM stories WHERE is_published = false AND deleted_at IS NULL) AS unpublished_stories,
+		       (SELECT COUNT(*) FROM stories
+		        WHERE submitted_at IS NOT NULL AND is_published = false AND deleted_at IS NULL) AS pending_moderation,
+		       (SELECT COUNT(*) FROM chapters WHERE deleted_at IS NULL) AS chapters
+	`
+	err := r.db.GetContext(ctx, &totals, query)
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// GetTopStories ranks stories by views between from and to
+func (r *SiteStatsRepository) GetTopStories(ctx context.Context, from, to time.Time, limit int) ([]models.TopStory, error) {
+	var stories []models.TopStory
+	query := `
+		SELECT s.id AS story_id, s.title, s.slug, s.author_name,
+		       SUM(v.views) AS views,
+		       (SELECT COUNT(DISTINCT e.user_id) FROM reading_events e
+		        WHERE e.story_id = s.id AND e.read_on BETWEEN $1 AND $2) AS readers
+		FROM story_views_daily v
+		INNER JOIN stories s ON v.story_id = s.id
+		WHERE v.view_date BETWEEN $1 AND $2 AND s.deleted_at IS NULL
+		GROUP BY s.id, s.title, s.slug, s.author_name
+		ORDER BY views DESC
+		LIMIT $3
+	`
+	err := r.db.SelectContext(ctx, &stories, query, from, to, limit)
+	return stories, err
+}
@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"web-be/models"
 )
 
@@ -30,7 +33,7 @@ func (r *StoryRepository) Create(ctx context.Context, story *models.Story) error
 
 func (r *StoryRepository) GetByID(ctx context.Context, id int) (*models.Story, error) {
 	var story models.Story
-	query := `SELECT * FROM stories WHERE id = $1`
+	query := `SELECT * FROM stories WHERE id = $1 AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &story, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -43,7 +46,7 @@ func (r *StoryRepository) GetByID(ctx context.Context, id int) (*models.Story, e
 
 func (r *StoryRepository) GetBySlug(ctx context.Context, slug string) (*models.Story, error) {
 	var story models.Story
-	query := `SELECT * FROM stories WHERE slug = $1`
+	query := `SELECT * FROM stories WHERE slug = $1 AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &story, query, slug)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -54,34 +57,61 @@ func (r *StoryRepository) GetBySlug(ctx context.Context, slug string) (*models.S
 	return &story, nil
 }
 
-func (r *StoryRepository) GetAll(ctx context.Context, limit, offset int) ([]models.Story, int64, error) {
+// storiesWithAllTags restricts a query to stories carrying every tag in the
+// int array bound to placeholder $n. The IDs must be distinct.
+func storiesWithAllTags(n int) string {
+	param := fmt.Sprintf("$%d::int[]", n)
+	return ` AND id IN (
+		SELECT story_id FROM story_tags WHERE tag_id = ANY(` + param + `)
+		GROUP BY story_id HAVING COUNT(*) = cardinality(` + param + `)
+	)`
+}
+
+// GetAll lists published stories; when tagIDs is non-empty only stories with
+// all of those tags are returned
+func (r *StoryRepository) GetAll(ctx context.Context, tagIDs []int, limit, offset int) ([]models.Story, int64, error) {
 	var stories []models.Story
 	var total int64
 
-	countQuery := `SELECT COUNT(*) FROM stories WHERE is_published = true`
-	err := r.db.GetContext(ctx, &total, countQuery)
+	countFilter, queryFilter := "", ""
+	var countArgs []interface{}
+	args := []interface{}{limit, offset}
+	if len(tagIDs) > 0 {
+		countFilter, queryFilter = storiesWithAllTags(1), storiesWithAllTags(3)
+		countArgs = append(countArgs, pq.Array(tagIDs))
+		args = append(args, pq.Array(tagIDs))
+	}
+
+	countQuery := `SELECT COUNT(*) FROM stories WHERE is_published = true AND deleted_at IS NULL` + countFilter
+	err := r.db.GetContext(ctx, &total, countQuery, countArgs...)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := `
 		SELECT * FROM stories 
-		WHERE is_published = true 
+		WHERE is_published = true AND deleted_at IS NULL` + queryFilter + `
 		ORDER BY updated_at DESC 
 		LIMIT $1 OFFSET $2
 	`
-	err = r.db.SelectContext(ctx, &stories, query, limit, offset)
+	err = r.db.SelectContext(ctx, &stories, query, args...)
 	return stories, total, err
 }
 
+// storiesInCategory matches stories filed under category $1 or one of its subcategories
+const storiesInCategory = `s.id IN (
+	SELECT sc.story_id FROM story_categories sc
+	INNER JOIN categories c ON sc.category_id = c.id
+	WHERE c.id = $1 OR c.parent_id = $1
+)`
+
 func (r *StoryRepository) GetByCategory(ctx context.Context, categoryID, limit, offset int) ([]models.Story, int64, error) {
 	var stories []models.Story
 	var total int64
 
 	countQuery := `
 		SELECT COUNT(*) FROM stories s
-		INNER JOIN story_categories sc ON s.id = sc.story_id
-		WHERE sc.category_id = $1 AND s.is_published = true
+		WHERE ` + storiesInCategory + ` AND s.is_published = true AND s.deleted_at IS NULL
 	`
 	err := r.db.GetContext(ctx, &total, countQuery, categoryID)
 	if err != nil {
@@ -90,8 +120,7 @@ func (r *StoryRepository) GetByCategory(ctx context.Context, categoryID, limit,
 
 	query := `
 		SELECT s.* FROM stories s
-		INNER JOIN story_categories sc ON s.id = sc.story_id
-		WHERE sc.category_id = $1 AND s.is_published = true
+		WHERE ` + storiesInCategory + ` AND s.is_published = true AND s.deleted_at IS NULL
 		ORDER BY s.updated_at DESC
 		LIMIT $2 OFFSET $3
 	`
@@ -99,54 +128,122 @@ func (r *StoryRepository) GetByCategory(ctx context.Context, categoryID, limit,
 	return stories, total, err
 }
 
-func (r *StoryRepository) Search(ctx context.Context, keyword string, limit, offset int) ([]models.Story, int64, error) {
+func (r *StoryRepository) Search(ctx context.Context, keyword string, tagIDs []int, limit, offset int) ([]models.Story, int64, error) {
 	var stories []models.Story
 	var total int64
 	searchPattern := "%" + keyword + "%"
 
+	countFilter, queryFilter := "", ""
+	countArgs := []interface{}{searchPattern}
+	args := []interface{}{searchPattern, limit, offset}
+	if len(tagIDs) > 0 {
+		countFilter, queryFilter = storiesWithAllTags(2), storiesWithAllTags(4)
+		countArgs = append(countArgs, pq.Array(tagIDs))
+		args = append(args, pq.Array(tagIDs))
+	}
+
 	countQuery := `
 		SELECT COUNT(*) FROM stories 
-		WHERE is_published = true 
-		AND (title ILIKE $1 OR description ILIKE $1)
-	`
-	err := r.db.GetContext(ctx, &total, countQuery, searchPattern)
+		WHERE is_published = true AND deleted_at IS NULL
+		AND (title ILIKE $1 OR description ILIKE $1)` + countFilter
+	err := r.db.GetContext(ctx, &total, countQuery, countArgs...)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := `
 		SELECT * FROM stories 
-		WHERE is_published = true 
-		AND (title ILIKE $1 OR description ILIKE $1)
+		WHERE is_published = true AND deleted_at IS NULL
+		AND (title ILIKE $1 OR description ILIKE $1)` + queryFilter + `
 		ORDER BY total_views DESC 
 		LIMIT $2 OFFSET $3
 	`
-	err = r.db.SelectContext(ctx, &stories, query, searchPattern, limit, offset)
+	err = r.db.SelectContext(ctx, &stories, query, args...)
 	return stories, total, err
 }
 
-func (r *StoryRepository) Update(ctx context.Context, story *models.Story) error {
+// Update saves a story; when the slug changed the old one is kept in the slug history
+func (r *StoryRepository) Update(ctx context.Context, story *models.Story, oldSlug string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		UPDATE stories 
 		SET title = $1, slug = $2, description = $3, cover_image_url = $4, 
 		    author_name = $5, status = $6, is_published = $7, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $8
 	`
-	_, err := r.db.ExecContext(ctx, query,
+	_, err = tx.ExecContext(ctx, query,
 		story.Title, story.Slug, story.Description, story.CoverImageURL,
 		story.AuthorName, story.Status, story.IsPublished, story.ID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if oldSlug != story.Slug {
+		// The story may be taking back one of its former slugs
+		if _, err := tx.ExecContext(ctx, `DELETE FROM story_slug_history WHERE old_slug = $1`, story.Slug); err != nil {
+			return err
+		}
+		query = `
+			INSERT INTO story_slug_history (old_slug, story_id) VALUES ($1, $2)
+			ON CONFLICT (old_slug) DO UPDATE SET story_id = EXCLUDED.story_id, created_at = CURRENT_TIMESTAMP
+		`
+		if _, err := tx.ExecContext(ctx, query, oldSlug, story.ID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SlugTaken reports whether slug is used, now or formerly, by a story other than storyID
+func (r *StoryRepository) SlugTaken(ctx context.Context, slug string, storyID int) (bool, error) {
+	var taken bool
+	query := `
+		SELECT EXISTS (SELECT 1 FROM stories WHERE slug = $1 AND id <> $2)
+		    OR EXISTS (SELECT 1 FROM story_slug_history WHERE old_slug = $1 AND story_id <> $2)
+	`
+	err := r.db.GetContext(ctx, &taken, query, slug, storyID)
+	return taken, err
+}
+
+// GetCurrentSlug returns the slug a former story slug now maps to, or "" if none
+func (r *StoryRepository) GetCurrentSlug(ctx context.Context, oldSlug string) (string, error) {
+	var slug string
+	query := `
+		SELECT s.slug FROM stories s
+		INNER JOIN story_slug_history h ON s.id = h.story_id
+		WHERE h.old_slug = $1 AND s.deleted_at IS NULL
+	`
+	err := r.db.GetContext(ctx, &slug, query, oldSlug)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return slug, err
 }
 
+// Delete moves a story to the trash; it stays restorable until purged
 func (r *StoryRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM stories WHERE id = $1`
+	query := `UPDATE stories SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
 
+// IncrementViews bumps the lifetime counter and today's row in story_views_daily
 func (r *StoryRepository) IncrementViews(ctx context.Context, id int) error {
-	query := `UPDATE stories SET total_views = total_views + 1 WHERE id = $1`
+	query := `
+		WITH updated AS (
+			UPDATE stories SET total_views = total_views + 1 WHERE id = $1 RETURNING id
+		)
+		INSERT INTO story_views_daily (story_id, view_date, views)
+		SELECT id, CURRENT_DATE, 1 FROM updated
+		ON CONFLICT (story_id, view_date) DO UPDATE SET views = story_views_daily.views + 1
+	`
 	_, err := r.db.ExecContext(ctx, query, id)
 	return err
 }
@@ -154,7 +251,7 @@ func (r *StoryRepository) IncrementViews(ctx context.Context, id int) error {
 func (r *StoryRepository) UpdateChapterCount(ctx context.Context, storyID int) error {
 	query := `
 		UPDATE stories 
-		SET total_chapters = (SELECT COUNT(*) FROM chapters WHERE story_id = $1 AND is_published = true),
+		SET total_chapters = (SELECT COUNT(*) FROM chapters WHERE story_id = $1 AND is_published = true AND deleted_at IS NULL),
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1
 	`
@@ -162,12 +259,31 @@ func (r *StoryRepository) UpdateChapterCount(ctx context.Context, storyID int) e
 	return err
 }
 
+// Publish sets whether a story is published; publishing takes it out of the review queue
 func (r *StoryRepository) Publish(ctx context.Context, id int, publish bool) error {
-	query := `UPDATE stories SET is_published = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	query := `
+		UPDATE stories
+		SET is_published = $1, submitted_at = CASE WHEN $1 THEN NULL ELSE submitted_at END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
 	_, err := r.db.ExecContext(ctx, query, publish, id)
 	return err
 }
 
+// SubmitForReview queues an unpublished story for an admin to publish,
+// keeping the original time if it was already queued
+func (r *StoryRepository) SubmitForReview(ctx context.Context, id int) (time.Time, error) {
+	var submittedAt time.Time
+	query := `
+		UPDATE stories SET submitted_at = COALESCE(submitted_at, CURRENT_TIMESTAMP)
+		WHERE id = $1
+		RETURNING submitted_at
+	`
+	err := r.db.GetContext(ctx, &submittedAt, query, id)
+	return submittedAt, err
+}
+
 // Category management
 func (r *StoryRepository) AddCategory(ctx context.Context, storyID, categoryID int) error {
 	query := `INSERT INTO story_categories (story_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
@@ -187,7 +303,7 @@ func (r *StoryRepository) GetCategories(ctx context.Context, storyID int) ([]mod
 		SELECT c.* FROM categories c
 		INNER JOIN story_categories sc ON c.id = sc.category_id
 		WHERE sc.story_id = $1
-		ORDER BY c.name
+		ORDER BY c.display_order, c.name
 	`
 	err := r.db.SelectContext(ctx, &categories, query, storyID)
 	return categories, err
@@ -221,13 +337,33 @@ func (r *StoryRepository) GetByAuthor(ctx context.Context, authorID, limit, offs
 	var stories []models.Story
 	var total int64
 
-	countQuery := `SELECT COUNT(*) FROM stories WHERE author_id = $1`
+	countQuery := `SELECT COUNT(*) FROM stories WHERE author_id = $1 AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &total, countQuery, authorID)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	query := `SELECT * FROM stories WHERE author_id = $1 ORDER BY updated_at DESC LIMIT $2 OFFSET $3`
+	query := `SELECT * FROM stories WHERE author_id = $1 AND deleted_at IS NULL ORDER BY updated_at DESC LIMIT $2 OFFSET $3`
+	err = r.db.SelectContext(ctx, &stories, query, authorID, limit, offset)
+	return stories, total, err
+}
+
+func (r *StoryRepository) GetPublishedByAuthor(ctx context.Context, authorID, limit, offset int) ([]models.Story, int64, error) {
+	var stories []models.Story
+	var total int64
+
+	countQuery := `SELECT COUNT(*) FROM stories WHERE author_id = $1 AND is_published = true AND deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &total, countQuery, authorID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT * FROM stories 
+		WHERE author_id = $1 AND is_published = true AND deleted_at IS NULL
+		ORDER BY updated_at DESC 
+		LIMIT $2 OFFSET $3
+	`
 	err = r.db.SelectContext(ctx, &stories, query, authorID, limit, offset)
 	return stories, total, err
 }
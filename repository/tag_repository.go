@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"web-be/models"
+)
+
+type TagRepository struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) GetByID(ctx context.Context, id int) (*models.Tag, error) {
+	var tag models.Tag
+	query := `SELECT * FROM tags WHERE id = $1`
+	err := r.db.GetContext(ctx, &tag, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *TagRepository) GetBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+	var tag models.Tag
+	query := `SELECT * FROM tags WHERE slug = $1`
+	err := r.db.GetContext(ctx, &tag, query, slug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetOrCreate returns the tag with the given slug, creating it when missing
+func (r *TagRepository) GetOrCreate(ctx context.Context, name, slug string) (*models.Tag, error) {
+	var tag models.Tag
+	query := `
+		INSERT INTO tags (name, slug) VALUES ($1, $2)
+		ON CONFLICT (slug) DO UPDATE SET slug = EXCLUDED.slug
+		RETURNING *
+	`
+	err := r.db.GetContext(ctx, &tag, query, name, slug)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// CreateSynonym adds a new tag that resolves to canonicalID
+func (r *TagRepository) CreateSynonym(ctx context.Context, tag *models.Tag) error {
+	query := `
+		INSERT INTO tags (name, slug, canonical_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowxContext(ctx, query, tag.Name, tag.Slug, tag.CanonicalID).Scan(&tag.ID, &tag.CreatedAt)
+}
+
+func (r *TagRepository) GetSynonyms(ctx context.Context, canonicalID int) ([]models.Tag, error) {
+	var tags []models.Tag
+	query := `SELECT * FROM tags WHERE canonical_id = $1 ORDER BY name`
+	err := r.db.SelectContext(ctx, &tags, query, canonicalID)
+	return tags, err
+}
+
+// Search returns canonical tags whose slug, or a synonym's slug, starts with
+// prefix, most used first. An empty prefix lists the most popular tags.
+func (r *TagRepository) Search(ctx context.Context, prefix string, limit int) ([]models.Tag, error) {
+	var tags []models.Tag
+	query := `
+		SELECT * FROM tags
+		WHERE canonical_id IS NULL
+		  AND id IN (SELECT COALESCE(canonical_id, id) FROM tags WHERE slug LIKE $1)
+		ORDER BY usage_count DESC, name
+		LIMIT $2
+	`
+	err := r.db.SelectContext(ctx, &tags, query, prefix+"%", limit)
+	return tags, err
+}
+
+func (r *TagRepository) GetByStory(ctx context.Context, storyID int) ([]models.Tag, error) {
+	var tags []models.Tag
+	query := `
+		SELECT t.* FROM tags t
+		INNER JOIN story_tags st ON t.id = st.tag_id
+		WHERE st.story_id = $1
+		ORDER BY t.usage_count DESC, t.name
+	`
+	err := r.db.SelectContext(ctx, &tags, query, storyID)
+	return tags, err
+}
+
+// GetStorySlugs returns the slugs of stories carrying the tag
+func (r *TagRepository) GetStorySlugs(ctx context.Context, tagID int) ([]string, error) {
+	var slugs []string
+	query := `
+		SELECT s.slug FROM stories s
+		INNER JOIN story_tags st ON s.id = st.story_id
+		WHERE st.tag_id = $1
+	`
+	err := r.db.SelectContext(ctx, &slugs, query, tagID)
+	return slugs, err
+}
+
+// SetStoryTags replaces a story's tags and refreshes usage counts
+func (r *TagRepository) SetStoryTags(ctx context.Context, storyID int, tagIDs []int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var removed []int
+	err = tx.SelectContext(ctx, &removed, `DELETE FROM story_tags WHERE story_id = $1 RETURNING tag_id`, storyID)
+	if err != nil {
+		return err
+	}
+
+	for _, tagID := range tagIDs {
+		_, err = tx.ExecContext(ctx, `INSERT INTO story_tags (story_id, tag_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, storyID, tagID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := refreshUsage(ctx, tx, append(removed, tagIDs...)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Merge moves every story from source to target and turns source, and any
+// synonyms of it, into synonyms of target
+func (r *TagRepository) Merge(ctx context.Context, sourceID, targetID int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`INSERT INTO story_tags (story_id, tag_id)
+		 SELECT story_id, $2 FROM story_tags WHERE tag_id = $1
+		 ON CONFLICT DO NOTHING`,
+		`DELETE FROM story_tags WHERE tag_id = $1`,
+		`UPDATE tags SET canonical_id = $2 WHERE canonical_id = $1`,
+		`UPDATE tags SET canonical_id = $2 WHERE id = $1`,
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt, sourceID, targetID); err != nil {
+			return err
+		}
+	}
+
+	if err := refreshUsage(ctx, tx, []int{sourceID, targetID}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func refreshUsage(ctx context.Context, tx *sqlx.Tx, tagIDs []int) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+	query := `
+		UPDATE tags SET usage_count = (SELECT COUNT(*) FROM story_tags WHERE tag_id = tags.id)
+		WHERE id = ANY($1)
+	`
+	_, err := tx.ExecContext(ctx, query, pq.Array(tagIDs))
+	return err
+}
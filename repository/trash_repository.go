@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"web-be/models"
+)
+
+type TrashRepository struct {
+	db *sqlx.DB
+}
+
+func NewTrashRepository(db *sqlx.DB) *TrashRepository {
+	return &TrashRepository{db: db}
+}
+
+// trashItems lists deleted stories and chapters with the author they belong to
+const trashItems = `
+	WITH trash AS (
+		SELECT 'story' AS type, s.id, s.id AS story_id, s.title AS story_title, s.slug AS story_slug,
+		       NULL::int AS chapter_number, NULL::varchar AS chapter_title, s.deleted_at, s.author_id
+		FROM stories s
+		WHERE s.deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'chapter', c.id, s.id, s.title, s.slug,
+		       c.chapter_number, c.title, c.deleted_at, s.author_id
+		FROM chapters c
+		INNER JOIN stories s ON c.story_id = s.id
+		WHERE c.deleted_at IS NOT NULL
+	)
+`
+
+// List returns trashed items, newest first. A nil authorID lists everyone's.
+func (r *TrashRepository) List(ctx context.Context, authorID *int, limit, offset int) ([]models.TrashItem, int64, error) {
+	var items []models.TrashItem
+	var total int64
+
+	countQuery := trashItems + `SELECT COUNT(*) FROM trash WHERE $1::int IS NULL OR author_id = $1`
+	err := r.db.GetContext(ctx, &total, countQuery, authorID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := trashItems + `
+		SELECT type, id, story_id, story_title, story_slug, chapter_number, chapter_title, deleted_at
+		FROM trash
+		WHERE $1::int IS NULL OR author_id = $1
+		ORDER BY deleted_at DESC, type, id
+		LIMIT $2 OFFSET $3
+	`
+	err = r.db.SelectContext(ctx, &items, query, authorID, limit, offset)
+	return items, total, err
+}
+
+// GetStory returns a story whether or not it is in the trash
+func (r *TrashRepository) GetStory(ctx context.Context, id int) (*models.Story, error) {
+	var story models.Story
+	query := `SELECT * FROM stories WHERE id = $1`
+	err := r.db.GetContext(ctx, &story, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &story, nil
+}
+
+func (r *TrashRepository) GetDeletedChapter(ctx context.Context, id int) (*models.Chapter, error) {
+	var chapter models.Chapter
+	query := `SELECT * FROM chapters WHERE id = $1 AND deleted_at IS NOT NULL`
+	err := r.db.GetContext(ctx, &chapter, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &chapter, nil
+}
+
+func (r *TrashRepository) RestoreStory(ctx context.Context, id int) error {
+	query := `UPDATE stories SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *TrashRepository) RestoreChapter(ctx context.Context, id int) error {
+	query := `UPDATE chapters SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// PurgeStory permanently deletes a trashed story with its chapters and history
+func (r *TrashRepository) PurgeStory(ctx context.Context, id int) error {
+	query := `DELETE FROM stories WHERE id = $1 AND deleted_at IS NOT NULL`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *TrashRepository) PurgeChapter(ctx context.Context, id int) error {
+	query := `DELETE FROM chapters WHERE id = $1 AND deleted_at IS NOT NULL`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// PurgeExpired permanently deletes everything trashed before cutoff
+func (r *TrashRepository) PurgeExpired(ctx context.Context, cutoff time.Time) (stories, chapters int64, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM chapters WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, 0, err
+	}
+	chapters, _ = result.RowsAffected()
+
+	result, err = tx.ExecContext(ctx, `DELETE FROM stories WHERE deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, 0, err
+	}
+	stories, _ = result.RowsAffected()
+
+	return stories, chapters, tx.Commit()
+}
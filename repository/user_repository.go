@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"web-be/models"
@@ -69,24 +72,109 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users 
-		SET full_name = $1, avatar_url = $2, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $3
+		SET full_name = $1, avatar_url = $2, language = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
 	`
-	_, err := r.db.ExecContext(ctx, query, user.FullName, user.AvatarURL, user.ID)
+	_, err := r.db.ExecContext(ctx, query, user.FullName, user.AvatarURL, user.Language, user.ID)
 	return err
 }
 
-func (r *UserRepository) GetAll(ctx context.Context, limit, offset int) ([]models.User, int64, error) {
+// UserFilter narrows the admin user listing; zero values are ignored
+type UserFilter struct {
+	Query         string
+	Role          string
+	Status        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+}
+
+func (r *UserRepository) Search(ctx context.Context, filter UserFilter, limit, offset int) ([]models.User, int64, error) {
 	var users []models.User
 	var total int64
 
-	countQuery := `SELECT COUNT(*) FROM users`
-	err := r.db.GetContext(ctx, &total, countQuery)
+	conditions := []string{"1=1"}
+	var args []interface{}
+	addArg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Query != "" {
+		p := addArg("%" + filter.Query + "%")
+		conditions = append(conditions, fmt.Sprintf("(username ILIKE %s OR email ILIKE %s OR full_name ILIKE %s)", p, p, p))
+	}
+	if filter.Role != "" {
+		conditions = append(conditions, "role = "+addArg(filter.Role))
+	}
+	switch filter.Status {
+	case models.UserStatusBanned:
+		conditions = append(conditions, "is_active = false")
+	case models.UserStatusSuspended:
+		conditions = append(conditions, "is_active = true AND suspended_until > CURRENT_TIMESTAMP")
+	case models.UserStatusActive:
+		conditions = append(conditions, "is_active = true AND (suspended_until IS NULL OR suspended_until <= CURRENT_TIMESTAMP)")
+	}
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, "created_at >= "+addArg(*filter.CreatedAfter))
+	}
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, "created_at < "+addArg(*filter.CreatedBefore))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	countQuery := `SELECT COUNT(*) FROM users WHERE ` + where
+	err := r.db.GetContext(ctx, &total, countQuery, args...)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	query := `SELECT * FROM users ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	err = r.db.SelectContext(ctx, &users, query, limit, offset)
+	query := fmt.Sprintf(`SELECT * FROM users WHERE %s ORDER BY created_at DESC LIMIT %s OFFSET %s`,
+		where, addArg(limit), addArg(offset))
+	err = r.db.SelectContext(ctx, &users, query, args...)
 	return users, total, err
 }
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id int, role string) error {
+	query := `UPDATE users SET role = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, role, id)
+	return err
+}
+
+// SetSuspension suspends the user until the given time; a nil until lifts it
+func (r *UserRepository) SetSuspension(ctx context.Context, id int, until *time.Time, reason *string) error {
+	query := `
+		UPDATE users 
+		SET suspended_until = $1, suspension_reason = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`
+	_, err := r.db.ExecContext(ctx, query, until, reason, id)
+	return err
+}
+
+// SetBanned bans (is_active = false) or unbans the user
+func (r *UserRepository) SetBanned(ctx context.Context, id int, banned bool, reason *string) error {
+	query := `
+		UPDATE users 
+		SET is_active = $1, ban_reason = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3
+	`
+	_, err := r.db.ExecContext(ctx, query, !banned, reason, id)
+	return err
+}
+
+func (r *UserRepository) SetMustResetPassword(ctx context.Context, id int, mustReset bool) error {
+	query := `UPDATE users SET must_reset_password = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, mustReset, id)
+	return err
+}
+
+// UpdatePassword stores a new password hash and clears any forced reset
+func (r *UserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	query := `
+		UPDATE users 
+		SET password_hash = $1, must_reset_password = false, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+	_, err := r.db.ExecContext(ctx, query, passwordHash, id)
+	return err
+}
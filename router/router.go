@@ -1,69 +1,247 @@
 package router
 
 import (
+	"time"
+
 	"web-be/handler"
 	"web-be/middleware"
+	"web-be/models"
+	"web-be/ratelimit"
+	"web-be/service"
 	"web-be/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
-	engine          *gin.Engine
-	jwtManager      *utils.JWTManager
-	authHandler     *handler.AuthHandler
-	storyHandler    *handler.StoryHandler
-	chapterHandler  *handler.ChapterHandler
-	bookmarkHandler *handler.BookmarkHandler
+	engine            *gin.Engine
+	jwtManager        *utils.JWTManager
+	authHandler       *handler.AuthHandler
+	storyHandler      *handler.StoryHandler
+	storyService      *service.StoryService
+	chapterHandler    *handler.ChapterHandler
+	bookmarkHandler   *handler.BookmarkHandler
+	tokenHandler      *handler.APITokenHandler
+	tokenService      *service.APITokenService
+	sessionHandler    *handler.SessionHandler
+	sessionService    *service.SessionService
+	adminHandler      *handler.AdminUserHandler
+	profileHandler    *handler.ProfileHandler
+	followHandler     *handler.FollowHandler
+	listHandler       *handler.ReadingListHandler
+	statsHandler      *handler.ReadingStatsHandler
+	analyticsHandler  *handler.AnalyticsHandler
+	adminStatsHandler *handler.AdminStatsHandler
+	tagHandler        *handler.TagHandler
+	categoryHandler   *handler.CategoryHandler
+	trashHandler      *handler.TrashHandler
+	healthHandler     *handler.HealthHandler
+	limiter           *ratelimit.Limiter
 }
 
 func NewRouter(
 	jwtManager *utils.JWTManager,
 	authHandler *handler.AuthHandler,
 	storyHandler *handler.StoryHandler,
+	storyService *service.StoryService,
 	chapterHandler *handler.ChapterHandler,
 	bookmarkHandler *handler.BookmarkHandler,
-
+	tokenHandler *handler.APITokenHandler,
+	tokenService *service.APITokenService,
+	sessionHandler *handler.SessionHandler,
+	sessionService *service.SessionService,
+	adminHandler *handler.AdminUserHandler,
+	profileHandler *handler.ProfileHandler,
+	followHandler *handler.FollowHandler,
+	listHandler *handler.ReadingListHandler,
+	statsHandler *handler.ReadingStatsHandler,
+	analyticsHandler *handler.AnalyticsHandler,
+	adminStatsHandler *handler.AdminStatsHandler,
+	tagHandler *handler.TagHandler,
+	categoryHandler *handler.CategoryHandler,
+	trashHandler *handler.TrashHandler,
+	healthHandler *handler.HealthHandler,
+	limiter *ratelimit.Limiter,
 ) *Router {
 	return &Router{
-		engine:          gin.Default(),
-		jwtManager:      jwtManager,
-		authHandler:     authHandler,
-		storyHandler:    storyHandler,
-		chapterHandler:  chapterHandler,
-		bookmarkHandler: bookmarkHandler,
+		engine:            gin.New(),
+		jwtManager:        jwtManager,
+		authHandler:       authHandler,
+		storyHandler:      storyHandler,
+		storyService:      storyService,
+		chapterHandler:    chapterHandler,
+		bookmarkHandler:   bookmarkHandler,
+		tokenHandler:      tokenHandler,
+		tokenService:      tokenService,
+		sessionHandler:    sessionHandler,
+		sessionService:    sessionService,
+		adminHandler:      adminHandler,
+		profileHandler:    profileHandler,
+		followHandler:     followHandler,
+		listHandler:       listHandler,
+		statsHandler:      statsHandler,
+		analyticsHandler:  analyticsHandler,
+		adminStatsHandler: adminStatsHandler,
+		tagHandler:        tagHandler,
+		categoryHandler:   categoryHandler,
+		trashHandler:      trashHandler,
+		healthHandler:     healthHandler,
+		limiter:           limiter,
 	}
 }
 
+// auth builds the authentication middleware; scopes lists what a personal
+// access token must carry to use the route (none means JWT only)
+func (r *Router) auth(scopes ...string) gin.HandlerFunc {
+	return middleware.AuthMiddleware(r.jwtManager, r.tokenService, r.sessionService, scopes...)
+}
+
+// optionalAuth identifies the user when a valid JWT is sent, without requiring one
+func (r *Router) optionalAuth() gin.HandlerFunc {
+	return middleware.OptionalAuthMiddleware(r.jwtManager, r.sessionService)
+}
+
+// httpCache adds ETags and 304s to a read route; anonymous responses may be
+// cached by browsers and CDNs for maxAge
+func (r *Router) httpCache(maxAge time.Duration) gin.HandlerFunc {
+	return middleware.HTTPCache(maxAge)
+}
+
+// rateLimit applies the named policy from RATE_LIMIT_POLICIES, if configured
+func (r *Router) rateLimit(policy string) gin.HandlerFunc {
+	return middleware.RateLimit(r.limiter, policy)
+}
+
 func (r *Router) Setup() *gin.Engine {
 	// CORS Middleware
 	//r.engine.Use(middleware.CORSMiddleware())
 
+	// The request ID comes first so every later log line carries it
+	r.engine.Use(middleware.RequestID())
+	r.engine.Use(middleware.AccessLog())
+
+	// Tracing and request metrics wrap everything else so they see the final status
+	r.engine.Use(middleware.Tracing())
+	r.engine.Use(middleware.Metrics())
+
+	// Panics become 500 responses inside the logged, traced and measured chain
+	r.engine.Use(middleware.Recovery())
+
+	// gzip or brotli, as the client prefers
+	r.engine.Use(middleware.Compress())
+
+	// Errors attached with c.Error become JSON responses
+	r.engine.Use(middleware.ErrorHandler())
+
+	// Probes for the orchestrator: livez is the process, readyz its dependencies
+	r.engine.GET("/livez", r.healthHandler.Livez)
+	r.engine.GET("/readyz", r.healthHandler.Readyz)
+
+	// Public verification keys for other services
+	r.engine.GET("/.well-known/jwks.json", r.authHandler.JWKS)
+
 	// API v1
 	api := r.engine.Group("/api/v1")
 	{
 		// Health check
-		api.GET("/health", func(c *gin.Context) {
-			c.JSON(200, gin.H{"status": "ok", "message": "Story Reader API is running"})
-		})
+		api.GET("/health", r.healthHandler.Readyz)
 
 		// Auth routes (public)
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", r.authHandler.Register)
-			auth.POST("/login", r.authHandler.Login)
+			auth.POST("/register", r.rateLimit("register"), r.authHandler.Register)
+			auth.POST("/login", r.rateLimit("login"), r.authHandler.Login)
 		}
 
 		// User profile (protected)
-		api.GET("/me", middleware.AuthMiddleware(r.jwtManager), r.authHandler.GetProfile)
-		api.PUT("/me", middleware.AuthMiddleware(r.jwtManager), r.authHandler.UpdateProfile)
+		api.GET("/me", r.auth(), r.authHandler.GetProfile)
+		api.PUT("/me", r.auth(), r.authHandler.UpdateProfile)
+		api.PUT("/me/password", r.auth(), r.authHandler.ChangePassword)
+		api.GET("/me/profile", r.auth(), r.profileHandler.GetSettings)
+		api.PUT("/me/profile", r.auth(), r.profileHandler.UpdateSettings)
+
+		// Public author profiles
+		users := api.Group("/users")
+		{
+			users.GET("/:username", r.optionalAuth(), r.profileHandler.GetPublicProfile)
+			users.GET("/:username/stories", r.profileHandler.GetAuthorStories)
+			users.GET("/:username/followers", r.optionalAuth(), r.followHandler.GetFollowers)
+			users.GET("/:username/following", r.optionalAuth(), r.followHandler.GetFollowing)
+			users.GET("/:username/follow", r.auth(), r.followHandler.GetStatus)
+			users.POST("/:username/follow", r.auth(), r.followHandler.Follow)
+			users.DELETE("/:username/follow", r.auth(), r.followHandler.Unfollow)
+		}
+
+		// Activity feed (protected)
+		api.GET("/feed", r.auth(), r.followHandler.GetFeed)
+
+		// Reading lists
+		api.GET("/me/lists", r.auth(), r.listHandler.GetMyLists)
+		api.POST("/me/lists", r.auth(), r.listHandler.Create)
+		api.GET("/me/followed-lists", r.auth(), r.listHandler.GetFollowedLists)
+		lists := api.Group("/lists")
+		{
+			lists.GET("/:code", r.optionalAuth(), r.listHandler.Get)
+			lists.GET("/:code/items", r.optionalAuth(), r.listHandler.GetItems)
+			lists.PUT("/:code", r.auth(), r.listHandler.Update)
+			lists.DELETE("/:code", r.auth(), r.listHandler.Delete)
+			lists.POST("/:code/items", r.auth(), r.listHandler.AddItem)
+			lists.PUT("/:code/items/order", r.auth(), r.listHandler.Reorder)
+			lists.DELETE("/:code/items/:story_id", r.auth(), r.listHandler.RemoveItem)
+			lists.POST("/:code/follow", r.auth(), r.listHandler.Follow)
+			lists.DELETE("/:code/follow", r.auth(), r.listHandler.Unfollow)
+		}
+
+		// Bookmarks (protected), kept in the built-in "reading" list
+		bookmarks := api.Group("/bookmarks")
+		bookmarks.Use(r.auth())
+		{
+			bookmarks.GET("", r.bookmarkHandler.GetMyBookmarks)
+			bookmarks.POST("/:story_id", r.bookmarkHandler.AddBookmark)
+			bookmarks.DELETE("/:story_id", r.bookmarkHandler.RemoveBookmark)
+			bookmarks.GET("/:story_id/status", r.bookmarkHandler.GetBookmarkStatus)
+		}
+
+		// Personal access tokens (protected, JWT only)
+		tokens := api.Group("/me/tokens")
+		tokens.Use(r.auth())
+		{
+			tokens.GET("", r.tokenHandler.List)
+			tokens.POST("", r.tokenHandler.Create)
+			tokens.DELETE("/:id", r.tokenHandler.Revoke)
+		}
+
+		// Active sessions (protected, JWT only)
+		sessions := api.Group("/me/sessions")
+		sessions.Use(r.auth())
+		{
+			sessions.GET("", r.sessionHandler.List)
+			sessions.DELETE("", r.sessionHandler.RevokeOthers)
+			sessions.DELETE("/:id", r.sessionHandler.Revoke)
+		}
 
 		// My stories (protected)
-		api.GET("/my-stories", middleware.AuthMiddleware(r.jwtManager), r.storyHandler.GetMyStories)
+		api.GET("/my-stories", r.auth(models.ScopeStoriesRead), r.storyHandler.GetMyStories)
+
+		// Continue reading (protected)
+		api.GET("/me/continue-reading", r.auth(), r.chapterHandler.GetContinueReading)
+
+		// Reading stats (protected)
+		api.GET("/me/stats", r.auth(), r.statsHandler.GetStats)
+		api.GET("/me/recap/:year", r.auth(), r.statsHandler.GetRecap)
+
+		// Trash (protected): deleted stories and chapters until purged
+		trash := api.Group("/me/trash")
+		trash.Use(r.auth())
+		{
+			trash.GET("", r.trashHandler.GetMyTrash)
+			trash.POST("/stories/:id/restore", r.trashHandler.RestoreStory)
+			trash.POST("/chapters/:id/restore", r.trashHandler.RestoreChapter)
+		}
 
 		// Reading history (protected)
 		history := api.Group("/history")
-		history.Use(middleware.AuthMiddleware(r.jwtManager))
+		history.Use(r.auth())
 		{
 			history.GET("", r.storyHandler.GetReadingHistory)
 			history.POST("/:story_id", r.storyHandler.UpdateReadingHistory)
@@ -73,44 +251,86 @@ func (r *Router) Setup() *gin.Engine {
 		// Category routes (public)
 		categories := api.Group("/categories")
 		{
-			categories.GET("", r.storyHandler.GetAllCategories)
-			categories.GET("/:slug/stories", r.storyHandler.GetByCategory)
+			categories.GET("", r.httpCache(5*time.Minute), r.categoryHandler.GetAll)
+			categories.GET("/:slug/stories", r.httpCache(time.Minute), r.categoryHandler.GetStories)
+		}
+
+		// Tag routes (public)
+		tags := api.Group("/tags")
+		{
+			tags.GET("", r.tagHandler.Search)
+			tags.GET("/:slug", r.tagHandler.Get)
+			tags.GET("/:slug/stories", r.httpCache(time.Minute), r.tagHandler.GetStories)
 		}
 
 		// Story routes
 		stories := api.Group("/stories")
+		stories.Use(middleware.StorySlugRedirect(r.storyService))
 		{
 			// Public routes
-			stories.GET("", r.storyHandler.GetAll)
-			stories.GET("/search", r.storyHandler.Search)
-			stories.GET("/:slug", r.storyHandler.GetBySlug)
-			stories.GET("/:slug/chapters", r.chapterHandler.GetByStory)
-			stories.GET("/:slug/chapters/:chapter_num", r.chapterHandler.GetChapter)
-			stories.GET("/:slug/stats", r.bookmark_handler.GetViewStats)
+			stories.GET("", r.httpCache(30*time.Second), r.storyHandler.GetAll)
+			stories.GET("/search", r.optionalAuth(), r.rateLimit("search"), r.httpCache(30*time.Second), r.storyHandler.Search)
+			stories.GET("/:slug", r.httpCache(time.Minute), r.storyHandler.GetBySlug)
+			stories.GET("/:slug/chapters", r.optionalAuth(), r.httpCache(time.Minute), r.chapterHandler.GetByStory)
+			stories.GET("/:slug/chapters/:chapter_num", r.optionalAuth(), r.rateLimit("chapter"), r.httpCache(5*time.Minute), r.chapterHandler.GetChapter)
+			stories.GET("/:slug/stats", r.bookmarkHandler.GetViewStats)
 
 			// Protected routes
-			storiesAuth := stories.Group("")
-			storiesAuth.Use(middleware.AuthMiddleware(r.jwtManager))
-			{
-				storiesAuth.POST("", r.storyHandler.Create)
-				storiesAuth.PUT("/:slug", r.storyHandler.Update)
-				storiesAuth.DELETE("/:slug", r.storyHandler.Delete)
-
-				// Chapter management
-				storiesAuth.POST("/:slug/chapters", r.chapterHandler.Create)
-				storiesAuth.PUT("/:slug/chapters/:chapter_num", r.chapterHandler.Update)
-				storiesAuth.DELETE("/:slug/chapters/:chapter_num", r.chapterHandler.Delete)
-			}
+			stories.POST("", r.auth(models.ScopeStoriesWrite), r.storyHandler.Create)
+			stories.PUT("/:slug", r.auth(models.ScopeStoriesWrite), r.storyHandler.Update)
+			stories.DELETE("/:slug", r.auth(models.ScopeStoriesWrite), r.storyHandler.Delete)
+			stories.POST("/:slug/submit", r.auth(models.ScopeStoriesWrite), r.storyHandler.SubmitForReview)
+
+			// Chapter management
+			stories.POST("/:slug/chapters", r.auth(models.ScopeChaptersWrite), r.chapterHandler.Create)
+			stories.PUT("/:slug/chapters/:chapter_num", r.auth(models.ScopeChaptersWrite), r.chapterHandler.Update)
+			stories.DELETE("/:slug/chapters/:chapter_num", r.auth(models.ScopeChaptersWrite), r.chapterHandler.Delete)
+
+			// Author analytics
+			stories.GET("/:slug/analytics", r.auth(models.ScopeStoriesRead), r.analyticsHandler.GetStoryAnalytics)
+			stories.GET("/:slug/analytics/export", r.auth(models.ScopeStoriesRead), r.analyticsHandler.ExportCSV)
+			stories.GET("/:slug/analytics/chapters/:chapter_num", r.auth(models.ScopeStoriesRead), r.analyticsHandler.GetChapterAnalytics)
+
+			// Reading progress
+			stories.PUT("/:slug/chapters/:chapter_num/progress", r.auth(), r.chapterHandler.SaveProgress)
 		}
 
 		// Admin routes
 		admin := api.Group("/admin")
-		admin.Use(middleware.AuthMiddleware(r.jwtManager))
+		admin.Use(r.auth())
 		admin.Use(middleware.RequireRole("admin"))
 		{
-			admin.GET("/users", r.authHandler.GetAllUsers)
-			admin.POST("/categories", r.storyHandler.CreateCategory)
+			// Dashboard
+			admin.GET("/stats", r.adminStatsHandler.GetStats)
+			admin.POST("/stats/refresh", r.adminStatsHandler.Refresh)
+
+			// User management
+			admin.GET("/users", r.adminHandler.List)
+			admin.GET("/users/:id", r.adminHandler.Get)
+			admin.POST("/users/:id/suspend", r.adminHandler.Suspend)
+			admin.DELETE("/users/:id/suspend", r.adminHandler.Unsuspend)
+			admin.POST("/users/:id/ban", r.adminHandler.Ban)
+			admin.DELETE("/users/:id/ban", r.adminHandler.Unban)
+			admin.PUT("/users/:id/role", r.adminHandler.ChangeRole)
+			admin.POST("/users/:id/force-password-reset", r.adminHandler.ForcePasswordReset)
+			admin.POST("/users/:id/impersonate", r.adminHandler.Impersonate)
+			admin.GET("/audit-logs", r.adminHandler.GetAuditLogs)
+
+			// Category management
+			admin.POST("/categories", r.categoryHandler.Create)
+			admin.PUT("/categories/order", r.categoryHandler.Reorder)
+			admin.PUT("/categories/:id", r.categoryHandler.Update)
+			admin.DELETE("/categories/:id", r.categoryHandler.Delete)
+			admin.POST("/categories/:id/merge", r.categoryHandler.Merge)
+
+			admin.POST("/tags/:slug/synonyms", r.tagHandler.AddSynonym)
+			admin.POST("/tags/:slug/merge", r.tagHandler.Merge)
 			admin.PUT("/stories/:id/publish", r.storyHandler.Publish)
+
+			// Trash
+			admin.GET("/trash", r.trashHandler.GetAll)
+			admin.DELETE("/trash/stories/:id", r.trashHandler.PurgeStory)
+			admin.DELETE("/trash/chapters/:id", r.trashHandler.PurgeChapter)
 		}
 
 	}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"web-be/dto"
+	"web-be/repository"
+	"web-be/tracing"
+)
+
+const (
+	defaultAdminStatsDays = 30
+	maxAdminStatsDays     = 366
+	// How far back the rollup is filled the first time it runs
+	adminStatsBackfillDays = 90
+	adminTopStoriesLimit   = 10
+)
+
+type AdminStatsService struct {
+	siteStatsRepo *repository.SiteStatsRepository
+}
+
+func NewAdminStatsService(siteStatsRepo *repository.SiteStatsRepository) *AdminStatsService {
+	return &AdminStatsService{siteStatsRepo: siteStatsRepo}
+}
+
+// RefreshRollup brings site_stats_daily up to date. It recomputes from the
+// day before the latest row so late events from yesterday are picked up.
+func (s *AdminStatsService) RefreshRollup(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "AdminStatsService.RefreshRollup")
+	defer span.End()
+
+	today := dateOf(time.Now())
+	from := today.AddDate(0, 0, -(adminStatsBackfillDays - 1))
+
+	latest, err := s.siteStatsRepo.GetLatestDay(ctx)
+	if err != nil {
+		return err
+	}
+	if latest != nil {
+		if start := dateOf(*latest).AddDate(0, 0, -1); start.After(from) {
+			from = start
+		}
+	}
+
+	if err := s.siteStatsRepo.Refresh(ctx, from, today); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "admin stats rollup refreshed", "from", from.Format(dto.DateLayout), "to", today.Format(dto.DateLayout))
+	return nil
+}
+
+func (s *AdminStatsService) GetStats(ctx context.Context, req *dto.DateRangeRequest) (*dto.AdminStatsResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminStatsService.GetStats")
+	defer span.End()
+
+	from, to, err := req.Resolve(defaultAdminStatsDays, maxAdminStatsDays)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.siteStatsRepo.GetTotals(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get site totals", "error", err)
+		return nil, errors.New("failed to get admin stats")
+	}
+
+	days, err := s.siteStatsRepo.GetDaily(ctx, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get site stats rollup", "error", err)
+		return nil, errors.New("failed to get admin stats")
+	}
+
+	topStories, err := s.siteStatsRepo.GetTopStories(ctx, from, to, adminTopStoriesLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get top stories", "error", err)
+		return nil, errors.New("failed to get admin stats")
+	}
+
+	response := &dto.AdminStatsResponse{
+		From:       from.Format(dto.DateLayout),
+		To:         to.Format(dto.DateLayout),
+		Totals:     *totals,
+		Daily:      make([]dto.AdminStatsDayResponse, 0, len(days)),
+		TopStories: topStories,
+	}
+	for _, day := range days {
+		response.Signups += day.Signups
+		response.NewStories += day.NewStories
+		response.NewChapters += day.NewChapters
+		response.ChaptersRead += day.ChaptersRead
+		response.Views += day.Views
+		response.Daily = append(response.Daily, dto.AdminStatsDayResponse{
+			Date:               day.Day.Format(dto.DateLayout),
+			Signups:            day.Signups,
+			ActiveUsers:        day.ActiveUsers,
+			MonthlyActiveUsers: day.MonthlyActiveUsers,
+			NewStories:         day.NewStories,
+			NewChapters:        day.NewChapters,
+			ChaptersRead:       day.ChaptersRead,
+			Views:              day.Views,
+		})
+	}
+	if len(days) > 0 {
+		latest := days[len(days)-1]
+		response.DailyActiveUsers = latest.ActiveUsers
+		response.MonthlyActiveUsers = latest.MonthlyActiveUsers
+		refreshedAt := latest.RefreshedAt.Format(time.RFC3339)
+		response.RefreshedAt = &refreshedAt
+	}
+
+	return response, nil
+}
@@ -0,0 +1,359 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+	"web-be/utils"
+)
+
+// impersonationTTL bounds how long a support session opened by an admin lasts
+const impersonationTTL = time.Hour
+
+type AdminUserService struct {
+	userRepo    *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+	auditRepo   *repository.AdminAuditRepository
+	jwtManager  *utils.JWTManager
+}
+
+func NewAdminUserService(
+	userRepo *repository.UserRepository,
+	sessionRepo *repository.SessionRepository,
+	auditRepo *repository.AdminAuditRepository,
+	jwtManager *utils.JWTManager,
+) *AdminUserService {
+	return &AdminUserService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		auditRepo:   auditRepo,
+		jwtManager:  jwtManager,
+	}
+}
+
+// AdminActor identifies the admin performing an action, for the audit log
+type AdminActor struct {
+	UserID    int
+	IPAddress string
+}
+
+func (s *AdminUserService) List(ctx context.Context, req *dto.AdminUserFilterRequest) ([]dto.AdminUserResponse, int64, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.List")
+	defer span.End()
+
+	filter := repository.UserFilter{
+		Query:  req.Query,
+		Role:   req.Role,
+		Status: req.Status,
+	}
+	if !req.CreatedFrom.IsZero() {
+		filter.CreatedAfter = &req.CreatedFrom
+	}
+	if !req.CreatedTo.IsZero() {
+		// created_to is inclusive of the whole day
+		end := req.CreatedTo.AddDate(0, 0, 1)
+		filter.CreatedBefore = &end
+	}
+
+	users, total, err := s.userRepo.Search(ctx, filter, req.GetLimit(), req.GetOffset())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to search users", "error", err)
+		return nil, 0, errors.New("failed to get users")
+	}
+
+	now := time.Now()
+	responses := make([]dto.AdminUserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, toAdminUserResponse(&users[i], now))
+	}
+	return responses, total, nil
+}
+
+func (s *AdminUserService) Get(ctx context.Context, userID int) (*dto.AdminUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.Get")
+	defer span.End()
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := toAdminUserResponse(user, time.Now())
+	return &response, nil
+}
+
+func (s *AdminUserService) Suspend(ctx context.Context, actor AdminActor, userID int, req *dto.SuspendUserRequest) (*dto.AdminUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.Suspend")
+	defer span.End()
+
+	if !req.Until.After(time.Now()) {
+		return nil, apperror.InvalidField("invalid_suspension_end", "until", "suspension end must be in the future")
+	}
+
+	user, err := s.getTargetUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetSuspension(ctx, user.ID, &req.Until, &req.Reason); err != nil {
+		slog.ErrorContext(ctx, "failed to suspend user", "error", err, "user_id", user.ID)
+		return nil, errors.New("failed to suspend user")
+	}
+	s.signOutEverywhere(ctx, user.ID)
+
+	user.SuspendedUntil = &req.Until
+	user.SuspensionReason = &req.Reason
+	s.record(ctx, actor, models.AuditActionSuspend, user.ID, map[string]interface{}{
+		"reason": req.Reason,
+		"until":  req.Until,
+	})
+	return s.response(user), nil
+}
+
+func (s *AdminUserService) Unsuspend(ctx context.Context, actor AdminActor, userID int) (*dto.AdminUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.Unsuspend")
+	defer span.End()
+
+	user, err := s.getTargetUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetSuspension(ctx, user.ID, nil, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to lift suspension", "error", err, "user_id", user.ID)
+		return nil, errors.New("failed to lift suspension")
+	}
+
+	user.SuspendedUntil = nil
+	user.SuspensionReason = nil
+	s.record(ctx, actor, models.AuditActionUnsuspend, user.ID, nil)
+	return s.response(user), nil
+}
+
+func (s *AdminUserService) Ban(ctx context.Context, actor AdminActor, userID int, req *dto.BanUserRequest) (*dto.AdminUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.Ban")
+	defer span.End()
+
+	user, err := s.getTargetUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetBanned(ctx, user.ID, true, &req.Reason); err != nil {
+		slog.ErrorContext(ctx, "failed to ban user", "error", err, "user_id", user.ID)
+		return nil, errors.New("failed to ban user")
+	}
+	s.signOutEverywhere(ctx, user.ID)
+
+	user.IsActive = false
+	user.BanReason = &req.Reason
+	s.record(ctx, actor, models.AuditActionBan, user.ID, map[string]interface{}{"reason": req.Reason})
+	return s.response(user), nil
+}
+
+func (s *AdminUserService) Unban(ctx context.Context, actor AdminActor, userID int) (*dto.AdminUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.Unban")
+	defer span.End()
+
+	user, err := s.getTargetUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetBanned(ctx, user.ID, false, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to unban user", "error", err, "user_id", user.ID)
+		return nil, errors.New("failed to unban user")
+	}
+
+	user.IsActive = true
+	user.BanReason = nil
+	s.record(ctx, actor, models.AuditActionUnban, user.ID, nil)
+	return s.response(user), nil
+}
+
+func (s *AdminUserService) ChangeRole(ctx context.Context, actor AdminActor, userID int, req *dto.ChangeRoleRequest) (*dto.AdminUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.ChangeRole")
+	defer span.End()
+
+	user, err := s.getTargetUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == req.Role {
+		return s.response(user), nil
+	}
+
+	if err := s.userRepo.UpdateRole(ctx, user.ID, req.Role); err != nil {
+		slog.ErrorContext(ctx, "failed to change role", "error", err, "user_id", user.ID)
+		return nil, errors.New("failed to change role")
+	}
+	// Existing tokens still carry the old role claim
+	s.signOutEverywhere(ctx, user.ID)
+
+	s.record(ctx, actor, models.AuditActionChangeRole, user.ID, map[string]interface{}{
+		"from": user.Role,
+		"to":   req.Role,
+	})
+	user.Role = req.Role
+	return s.response(user), nil
+}
+
+func (s *AdminUserService) ForcePasswordReset(ctx context.Context, actor AdminActor, userID int) (*dto.AdminUserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.ForcePasswordReset")
+	defer span.End()
+
+	user, err := s.getTargetUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetMustResetPassword(ctx, user.ID, true); err != nil {
+		slog.ErrorContext(ctx, "failed to force password reset", "error", err, "user_id", user.ID)
+		return nil, errors.New("failed to force password reset")
+	}
+	s.signOutEverywhere(ctx, user.ID)
+
+	user.MustResetPassword = true
+	s.record(ctx, actor, models.AuditActionForcePasswordReset, user.ID, nil)
+	return s.response(user), nil
+}
+
+// Impersonate issues a short-lived, read-only token that lets support see the
+// API as the target user. The session is visible in the user's session list.
+func (s *AdminUserService) Impersonate(ctx context.Context, actor AdminActor, userID int, req *dto.ImpersonateRequest, userAgent string) (*dto.ImpersonationResponse, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.Impersonate")
+	defer span.End()
+
+	user, err := s.getTargetUser(ctx, actor, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == "admin" {
+		return nil, apperror.Forbidden("cannot_impersonate_admin", "cannot impersonate another admin")
+	}
+	if !user.IsActive {
+		return nil, apperror.Forbidden("cannot_impersonate_banned", "cannot impersonate a banned user")
+	}
+
+	expiresAt := time.Now().Add(impersonationTTL)
+	session := &models.Session{
+		UserID:         user.ID,
+		ExpiresAt:      expiresAt,
+		ImpersonatorID: &actor.UserID,
+	}
+	if userAgent != "" {
+		session.UserAgent = &userAgent
+	}
+	if actor.IPAddress != "" {
+		session.IPAddress = &actor.IPAddress
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		slog.ErrorContext(ctx, "failed to create impersonation session", "error", err, "user_id", user.ID)
+		return nil, errors.New("failed to start impersonation")
+	}
+
+	token, err := s.jwtManager.GenerateImpersonationToken(user.ID, user.Username, user.Role, session.ID, actor.UserID, impersonationTTL)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	s.record(ctx, actor, models.AuditActionImpersonate, user.ID, map[string]interface{}{
+		"reason":     req.Reason,
+		"session_id": session.ID,
+		"expires_at": expiresAt,
+	})
+
+	return &dto.ImpersonationResponse{
+		Token:     token,
+		ExpiresAt: expiresAt,
+		ReadOnly:  true,
+		User:      toUserResponse(user),
+	}, nil
+}
+
+func (s *AdminUserService) GetAuditLogs(ctx context.Context, targetUserID *int, limit, offset int) ([]models.AdminAuditLogWithUsers, int64, error) {
+	ctx, span := tracing.Start(ctx, "AdminUserService.GetAuditLogs")
+	defer span.End()
+
+	return s.auditRepo.List(ctx, targetUserID, limit, offset)
+}
+
+func (s *AdminUserService) getUser(ctx context.Context, userID int) (*models.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, apperror.NotFound("user_not_found", "user not found")
+	}
+	return user, nil
+}
+
+// getTargetUser loads the user an admin wants to act on, refusing self-moderation
+func (s *AdminUserService) getTargetUser(ctx context.Context, actor AdminActor, userID int) (*models.User, error) {
+	if actor.UserID == userID {
+		return nil, apperror.Forbidden("self_action_forbidden", "you cannot perform this action on your own account")
+	}
+	return s.getUser(ctx, userID)
+}
+
+func (s *AdminUserService) signOutEverywhere(ctx context.Context, userID int) {
+	if _, err := s.sessionRepo.RevokeAllExcept(ctx, userID, 0); err != nil {
+		slog.ErrorContext(ctx, "failed to revoke user sessions", "error", err, "user_id", userID)
+	}
+}
+
+// record writes an audit entry; failures are logged but don't undo the action
+func (s *AdminUserService) record(ctx context.Context, actor AdminActor, action string, targetUserID int, details map[string]interface{}) {
+	payload := []byte("{}")
+	if details != nil {
+		if b, err := json.Marshal(details); err == nil {
+			payload = b
+		}
+	}
+
+	entry := &models.AdminAuditLog{
+		ActorID:      &actor.UserID,
+		Action:       action,
+		TargetUserID: &targetUserID,
+		Details:      payload,
+	}
+	if actor.IPAddress != "" {
+		entry.IPAddress = &actor.IPAddress
+	}
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "failed to write admin audit log", "error", err, "action", action, "actor_id", actor.UserID, "target_user_id", targetUserID)
+		return
+	}
+	slog.InfoContext(ctx, "admin action", "action", action, "actor_id", actor.UserID, "target_user_id", targetUserID)
+}
+
+func (s *AdminUserService) response(user *models.User) *dto.AdminUserResponse {
+	response := toAdminUserResponse(user, time.Now())
+	return &response
+}
+
+func toAdminUserResponse(user *models.User, now time.Time) dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		FullName:          user.FullName,
+		AvatarURL:         user.AvatarURL,
+		Role:              user.Role,
+		Status:            user.Status(now),
+		SuspendedUntil:    user.SuspendedUntil,
+		SuspensionReason:  user.SuspensionReason,
+		BanReason:         user.BanReason,
+		MustResetPassword: user.MustResetPassword,
+		CreatedAt:         user.CreatedAt,
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+)
+
+const (
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 366
+)
+
+type AnalyticsService struct {
+	analyticsRepo *repository.AnalyticsRepository
+	storyRepo     *repository.StoryRepository
+	chapterRepo   *repository.ChapterRepository
+	profileRepo   *repository.ProfileRepository
+}
+
+func NewAnalyticsService(
+	analyticsRepo *repository.AnalyticsRepository,
+	storyRepo *repository.StoryRepository,
+	chapterRepo *repository.ChapterRepository,
+	profileRepo *repository.ProfileRepository,
+) *AnalyticsService {
+	return &AnalyticsService{
+		analyticsRepo: analyticsRepo,
+		storyRepo:     storyRepo,
+		chapterRepo:   chapterRepo,
+		profileRepo:   profileRepo,
+	}
+}
+
+// GetStoryAnalytics returns the dashboard for one story: lifetime figures,
+// a daily series and per-chapter totals with drop-off
+func (s *AnalyticsService) GetStoryAnalytics(ctx context.Context, storySlug string, userID int, userRole string, req *dto.DateRangeRequest) (*dto.StoryAnalyticsResponse, error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.GetStoryAnalytics")
+	defer span.End()
+
+	from, to, err := req.Resolve(defaultAnalyticsDays, maxAnalyticsDays)
+	if err != nil {
+		return nil, err
+	}
+
+	story, err := s.getOwnedStory(ctx, storySlug, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.analyticsRepo.GetStoryDaily(ctx, story.ID, story.AuthorID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get story analytics", "error", err, "story_id", story.ID)
+		return nil, errors.New("failed to get story analytics")
+	}
+
+	chapters, err := s.analyticsRepo.GetChapterTotals(ctx, story.ID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get chapter analytics", "error", err, "story_id", story.ID)
+		return nil, errors.New("failed to get story analytics")
+	}
+
+	uniqueReaders, err := s.analyticsRepo.CountUniqueReaders(ctx, story.ID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count unique readers", "error", err, "story_id", story.ID)
+		return nil, errors.New("failed to get story analytics")
+	}
+
+	bookmarks, err := s.analyticsRepo.CountBookmarks(ctx, story.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count bookmarks", "error", err, "story_id", story.ID)
+		return nil, errors.New("failed to get story analytics")
+	}
+
+	var followers int64
+	if story.AuthorID != nil {
+		followers, err = s.profileRepo.CountFollowers(ctx, *story.AuthorID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to count followers", "error", err, "author_id", *story.AuthorID)
+			return nil, errors.New("failed to get story analytics")
+		}
+	}
+
+	response := &dto.StoryAnalyticsResponse{
+		StoryID:       story.ID,
+		StoryTitle:    story.Title,
+		StorySlug:     story.Slug,
+		From:          from.Format(dto.DateLayout),
+		To:            to.Format(dto.DateLayout),
+		TotalViews:    story.TotalViews,
+		TotalChapters: story.TotalChapters,
+		Rating:        story.Rating,
+		Bookmarks:     bookmarks,
+		Followers:     followers,
+		UniqueReaders: uniqueReaders,
+		Daily:         make([]dto.StoryAnalyticsDayResponse, 0, len(days)),
+		Chapters:      make([]dto.ChapterAnalyticsResponse, 0, len(chapters)),
+	}
+	for _, day := range days {
+		response.Views += day.Views
+		response.NewBookmarks += day.NewBookmarks
+		response.NewFollowers += day.NewFollowers
+		response.Daily = append(response.Daily, dto.StoryAnalyticsDayResponse{
+			Date:          day.Date.Format(dto.DateLayout),
+			Views:         day.Views,
+			UniqueReaders: day.UniqueReaders,
+			NewBookmarks:  day.NewBookmarks,
+			NewFollowers:  day.NewFollowers,
+		})
+	}
+	for _, ch := range chapters {
+		response.Chapters = append(response.Chapters, toChapterAnalyticsResponse(ch))
+	}
+
+	return response, nil
+}
+
+// GetChapterAnalytics returns the daily series for one chapter
+func (s *AnalyticsService) GetChapterAnalytics(ctx context.Context, storySlug string, chapterNum int, userID int, userRole string, req *dto.DateRangeRequest) (*dto.ChapterDailyAnalyticsResponse, error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.GetChapterAnalytics")
+	defer span.End()
+
+	from, to, err := req.Resolve(defaultAnalyticsDays, maxAnalyticsDays)
+	if err != nil {
+		return nil, err
+	}
+
+	story, err := s.getOwnedStory(ctx, storySlug, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	chapter, err := s.chapterRepo.GetByStoryAndNumber(ctx, story.ID, chapterNum)
+	if err != nil {
+		return nil, err
+	}
+	if chapter == nil {
+		return nil, apperror.NotFound("chapter_not_found", "chapter not found")
+	}
+
+	days, err := s.analyticsRepo.GetChapterDaily(ctx, chapter.ID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get chapter analytics", "error", err, "chapter_id", chapter.ID)
+		return nil, errors.New("failed to get chapter analytics")
+	}
+
+	response := &dto.ChapterDailyAnalyticsResponse{
+		ChapterID:     chapter.ID,
+		ChapterNumber: chapter.ChapterNumber,
+		Title:         chapter.Title,
+		From:          from.Format(dto.DateLayout),
+		To:            to.Format(dto.DateLayout),
+		Daily:         make([]dto.ChapterAnalyticsDayResponse, 0, len(days)),
+	}
+	for _, day := range days {
+		response.Daily = append(response.Daily, dto.ChapterAnalyticsDayResponse{
+			Date:          day.Date.Format(dto.DateLayout),
+			Views:         day.Views,
+			UniqueReaders: day.UniqueReaders,
+			Completions:   day.Completions,
+		})
+	}
+
+	return response, nil
+}
+
+// ExportCSV returns a report as CSV rows, header first, and a file name for it
+func (s *AnalyticsService) ExportCSV(ctx context.Context, storySlug string, userID int, userRole string, req *dto.ExportAnalyticsRequest) (string, [][]string, error) {
+	ctx, span := tracing.Start(ctx, "AnalyticsService.ExportCSV")
+	defer span.End()
+
+	analytics, err := s.GetStoryAnalytics(ctx, storySlug, userID, userRole, &req.DateRangeRequest)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var rows [][]string
+	report := req.Report
+	if report == "" {
+		report = "daily"
+	}
+
+	switch report {
+	case "chapters":
+		rows = append(rows, []string{"chapter_number", "title", "views", "unique_readers", "completions", "continued_readers", "drop_off_percent"})
+		for _, ch := range analytics.Chapters {
+			dropOff := ""
+			if ch.DropOffPercent != nil {
+				dropOff = strconv.FormatFloat(*ch.DropOffPercent, 'f', 1, 64)
+			}
+			rows = append(rows, []string{
+				strconv.Itoa(ch.ChapterNumber), ch.Title, strconv.FormatInt(ch.Views, 10),
+				strconv.Itoa(ch.UniqueReaders), strconv.Itoa(ch.Completions), strconv.Itoa(ch.ContinuedReaders), dropOff,
+			})
+		}
+	default:
+		rows = append(rows, []string{"date", "views", "unique_readers", "new_bookmarks", "new_followers"})
+		for _, day := range analytics.Daily {
+			rows = append(rows, []string{
+				day.Date, strconv.FormatInt(day.Views, 10), strconv.Itoa(day.UniqueReaders),
+				strconv.Itoa(day.NewBookmarks), strconv.Itoa(day.NewFollowers),
+			})
+		}
+	}
+
+	filename := fmt.Sprintf("%s-%s-%s-%s.csv", analytics.StorySlug, report, analytics.From, analytics.To)
+	return filename, rows, nil
+}
+
+func (s *AnalyticsService) getOwnedStory(ctx context.Context, storySlug string, userID int, userRole string) (*models.Story, error) {
+	story, err := s.storyRepo.GetBySlug(ctx, storySlug)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil {
+		return nil, apperror.NotFound("story_not_found", "story not found")
+	}
+
+	if userRole != "admin" && (story.AuthorID == nil || *story.AuthorID != userID) {
+		return nil, apperror.Forbidden("forbidden", "you don't have permission to view analytics for this story")
+	}
+	return story, nil
+}
+
+func toChapterAnalyticsResponse(ch models.ChapterAnalytics) dto.ChapterAnalyticsResponse {
+	response := dto.ChapterAnalyticsResponse{
+		ChapterID:        ch.ChapterID,
+		ChapterNumber:    ch.ChapterNumber,
+		Title:            ch.Title,
+		Views:            ch.Views,
+		UniqueReaders:    ch.UniqueReaders,
+		Completions:      ch.Completions,
+		ContinuedReaders: ch.ContinuedReaders,
+	}
+	if ch.HasNext && ch.UniqueReaders > 0 {
+		dropOff := 100 * float64(ch.UniqueReaders-ch.ContinuedReaders) / float64(ch.UniqueReaders)
+		dropOff = float64(int(dropOff*10+0.5)) / 10
+		response.DropOffPercent = &dropOff
+	}
+	return response
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+	"web-be/utils"
+)
+
+type APITokenService struct {
+	tokenRepo *repository.APITokenRepository
+	userRepo  *repository.UserRepository
+}
+
+func NewAPITokenService(tokenRepo *repository.APITokenRepository, userRepo *repository.UserRepository) *APITokenService {
+	return &APITokenService{
+		tokenRepo: tokenRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *APITokenService) Create(ctx context.Context, userID int, req *dto.CreateAPITokenRequest) (*dto.CreatedAPITokenResponse, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.Create")
+	defer span.End()
+
+	plaintext, err := utils.GenerateAPIToken()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate api token", "error", err, "user_id", userID)
+		return nil, errors.New("failed to create token")
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		t := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	token := &models.APIToken{
+		UserID:      userID,
+		Name:        req.Name,
+		TokenPrefix: plaintext[:len(utils.APITokenPrefix)+6],
+		TokenHash:   utils.HashAPIToken(plaintext),
+		Scopes:      req.Scopes,
+		ExpiresAt:   expiresAt,
+	}
+
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		slog.ErrorContext(ctx, "failed to store api token", "error", err, "user_id", userID)
+		return nil, errors.New("failed to create token")
+	}
+
+	slog.InfoContext(ctx, "api token created", "token_id", token.ID, "user_id", userID, "scopes", req.Scopes)
+	return &dto.CreatedAPITokenResponse{
+		APITokenResponse: toAPITokenResponse(token),
+		Token:            plaintext,
+	}, nil
+}
+
+func (s *APITokenService) List(ctx context.Context, userID int) ([]dto.APITokenResponse, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.List")
+	defer span.End()
+
+	tokens, err := s.tokenRepo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.APITokenResponse, 0, len(tokens))
+	for i := range tokens {
+		responses = append(responses, toAPITokenResponse(&tokens[i]))
+	}
+	return responses, nil
+}
+
+func (s *APITokenService) Revoke(ctx context.Context, userID, tokenID int) error {
+	ctx, span := tracing.Start(ctx, "APITokenService.Revoke")
+	defer span.End()
+
+	revoked, err := s.tokenRepo.Revoke(ctx, tokenID, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to revoke api token", "error", err, "token_id", tokenID, "user_id", userID)
+		return errors.New("failed to revoke token")
+	}
+	if !revoked {
+		return apperror.NotFound("token_not_found", "token not found")
+	}
+
+	slog.InfoContext(ctx, "api token revoked", "token_id", tokenID, "user_id", userID)
+	return nil
+}
+
+// Authenticate resolves a plaintext token to its owner, rejecting revoked,
+// expired or deactivated ones, and records when it was last used
+func (s *APITokenService) Authenticate(ctx context.Context, plaintext string) (*models.User, *models.APIToken, error) {
+	ctx, span := tracing.Start(ctx, "APITokenService.Authenticate")
+	defer span.End()
+
+	token, err := s.tokenRepo.GetByHash(ctx, utils.HashAPIToken(plaintext))
+	if err != nil {
+		return nil, nil, err
+	}
+	if token == nil || !token.IsUsable(time.Now()) {
+		return nil, nil, apperror.Unauthorized("invalid_token", "invalid or expired token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil || !user.IsActive || user.IsSuspended(time.Now()) {
+		return nil, nil, apperror.Unauthorized("invalid_token", "invalid or expired token")
+	}
+
+	if err := s.tokenRepo.TouchLastUsed(ctx, token.ID); err != nil {
+		slog.WarnContext(ctx, "failed to record api token usage", "error", err, "token_id", token.ID)
+	}
+
+	return user, token, nil
+}
+
+func toAPITokenResponse(token *models.APIToken) dto.APITokenResponse {
+	return dto.APITokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.Scopes,
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
@@ -3,33 +3,42 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
+	"web-be/apperror"
 	"web-be/dto"
+	"web-be/metrics"
 	"web-be/models"
 	"web-be/repository"
+	"web-be/tracing"
 	"web-be/utils"
 )
 
 type AuthService struct {
-	userRepo   *repository.UserRepository
-	jwtManager *utils.JWTManager
+	userRepo    *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+	jwtManager  *utils.JWTManager
 }
 
-func NewAuthService(userRepo *repository.UserRepository, jwtManager *utils.JWTManager) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, sessionRepo *repository.SessionRepository, jwtManager *utils.JWTManager) *AuthService {
 	return &AuthService{
-		userRepo:   userRepo,
-		jwtManager: jwtManager,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		jwtManager:  jwtManager,
 	}
 }
 
-func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest, userAgent, ipAddress string) (*dto.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Register")
+	defer span.End()
+
 	// Check if email exists
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, err
 	}
 	if existingUser != nil {
-		return nil, errors.New("email already registered")
+		return nil, apperror.Conflict("email_taken", "email already registered")
 	}
 
 	// Check if username exists
@@ -38,7 +47,7 @@ func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) (*
 		return nil, err
 	}
 	if existingUser != nil {
-		return nil, errors.New("username already taken")
+		return nil, apperror.Conflict("username_taken", "username already taken")
 	}
 
 	// Hash password
@@ -65,83 +74,138 @@ func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) (*
 	if err != nil {
 		return nil, errors.New("failed to create user")
 	}
+	metrics.Registrations.Inc()
 
 	// Generate token
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.Role)
+	token, err := s.issueToken(ctx, user, userAgent, ipAddress)
 	if err != nil {
-		return nil, errors.New("failed to generate token")
+		return nil, err
 	}
 
 	return &dto.AuthResponse{
 		Token: token,
-		User: dto.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			FullName:  user.FullName,
-			AvatarURL: user.AvatarURL,
-			Role:      user.Role,
-		},
+		User:  toUserResponse(user),
 	}, nil
 }
 
-func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest, userAgent, ipAddress string) (*dto.AuthResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer span.End()
+
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
 		return nil, err
 	}
-	if user == nil {
-		return nil, errors.New("invalid email or password")
+	if user == nil || !utils.CheckPassword(req.Password, user.PasswordHash) {
+		metrics.Logins.WithLabelValues("invalid_credentials").Inc()
+		return nil, apperror.Unauthorized("invalid_credentials", "invalid email or password")
 	}
 
-	if !utils.CheckPassword(req.Password, user.PasswordHash) {
-		return nil, errors.New("invalid email or password")
+	if user.IsSuspended(time.Now()) {
+		metrics.Logins.WithLabelValues("suspended").Inc()
+		until := user.SuspendedUntil.Format(time.RFC3339)
+		if user.SuspensionReason != nil {
+			return nil, apperror.Forbidden("account_suspended", "account suspended until %s: %s").WithArgs(until, *user.SuspensionReason)
+		}
+		return nil, apperror.Forbidden("account_suspended", "account suspended until %s").WithArgs(until)
 	}
 
-	token, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.Role)
+	token, err := s.issueToken(ctx, user, userAgent, ipAddress)
 	if err != nil {
-		return nil, errors.New("failed to generate token")
+		return nil, err
 	}
+	metrics.Logins.WithLabelValues("success").Inc()
 
 	return &dto.AuthResponse{
-		Token: token,
-		User: dto.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			FullName:  user.FullName,
-			AvatarURL: user.AvatarURL,
-			Role:      user.Role,
-		},
+		Token:             token,
+		User:              toUserResponse(user),
+		MustResetPassword: user.MustResetPassword,
 	}, nil
 }
 
+// ChangePassword replaces the user's password and signs out their other sessions
+func (s *AuthService) ChangePassword(ctx context.Context, userID, currentSessionID int, req *dto.ChangePasswordRequest) error {
+	ctx, span := tracing.Start(ctx, "AuthService.ChangePassword")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return apperror.NotFound("user_not_found", "user not found")
+	}
+
+	if !utils.CheckPassword(req.CurrentPassword, user.PasswordHash) {
+		return apperror.InvalidField("incorrect_password", "current_password", "current password is incorrect")
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, user.ID, hashedPassword); err != nil {
+		return errors.New("failed to update password")
+	}
+
+	_, _ = s.sessionRepo.RevokeAllExcept(ctx, user.ID, currentSessionID)
+	return nil
+}
+
+// issueToken opens a new session for the user and signs a token bound to it
+func (s *AuthService) issueToken(ctx context.Context, user *models.User, userAgent, ipAddress string) (string, error) {
+	session := &models.Session{
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(s.jwtManager.Expiry()),
+	}
+	if len(userAgent) > 500 {
+		userAgent = userAgent[:500]
+	}
+	if userAgent != "" {
+		session.UserAgent = &userAgent
+	}
+	if ipAddress != "" {
+		session.IPAddress = &ipAddress
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return "", errors.New("failed to create session")
+	}
+
+	token, err := s.jwtManager.GenerateToken(user.ID, user.Username, user.Role, language(user), session.ID)
+	if err != nil {
+		return "", errors.New("failed to generate token")
+	}
+	return token, nil
+}
+
 func (s *AuthService) GetProfile(ctx context.Context, userID int) (*dto.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.GetProfile")
+	defer span.End()
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, apperror.NotFound("user_not_found", "user not found")
 	}
 
-	return &dto.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		FullName:  user.FullName,
-		AvatarURL: user.AvatarURL,
-		Role:      user.Role,
-	}, nil
+	response := toUserResponse(user)
+	return &response, nil
 }
 
 func (s *AuthService) UpdateProfile(ctx context.Context, userID int, req *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
+	ctx, span := tracing.Start(ctx, "AuthService.UpdateProfile")
+	defer span.End()
+
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, apperror.NotFound("user_not_found", "user not found")
 	}
 
 	if req.FullName != nil {
@@ -150,39 +214,44 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID int, req *dto.Up
 	if req.AvatarURL != nil {
 		user.AvatarURL = req.AvatarURL
 	}
+	if req.Language != nil {
+		// An empty language clears the preference
+		user.Language = req.Language
+		if *req.Language == "" {
+			user.Language = nil
+		}
+	}
 
 	err = s.userRepo.Update(ctx, user)
 	if err != nil {
 		return nil, errors.New("failed to update profile")
 	}
 
-	return &dto.UserResponse{
+	response := toUserResponse(user)
+	return &response, nil
+}
+
+// JWKS exposes the public verification keys for other services
+func (s *AuthService) JWKS() utils.JWKSet {
+	return s.jwtManager.JWKS()
+}
+
+func toUserResponse(user *models.User) dto.UserResponse {
+	return dto.UserResponse{
 		ID:        user.ID,
 		Username:  user.Username,
 		Email:     user.Email,
 		FullName:  user.FullName,
 		AvatarURL: user.AvatarURL,
 		Role:      user.Role,
-	}, nil
-}
-
-func (s *AuthService) GetAllUsers(ctx context.Context, limit, offset int) ([]dto.UserResponse, int64, error) {
-	users, total, err := s.userRepo.GetAll(ctx, limit, offset)
-	if err != nil {
-		return nil, 0, err
+		Language:  user.Language,
 	}
+}
 
-	var responses []dto.UserResponse
-	for _, user := range users {
-		responses = append(responses, dto.UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			FullName:  user.FullName,
-			AvatarURL: user.AvatarURL,
-			Role:      user.Role,
-		})
+// language returns the user's preferred language, or "" when none is set
+func language(user *models.User) string {
+	if user.Language == nil {
+		return ""
 	}
-
-	return responses, total, nil
+	return *user.Language
 }
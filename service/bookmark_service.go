@@ -5,78 +5,101 @@ import (
 	"errors"
 	"log/slog"
 
+	"web-be/apperror"
 	"web-be/models"
 	"web-be/repository"
+	"web-be/tracing"
 )
 
 type BookmarkService struct {
 	bookmarkRepo *repository.BookmarkRepository
 	storyRepo    *repository.StoryRepository
+	listService  *ReadingListService
 }
 
 func NewBookmarkService(
 	bookmarkRepo *repository.BookmarkRepository,
 	storyRepo *repository.StoryRepository,
+	listService *ReadingListService,
 ) *BookmarkService {
 	return &BookmarkService{
 		bookmarkRepo: bookmarkRepo,
 		storyRepo:    storyRepo,
+		listService:  listService,
 	}
 }
 
 func (s *BookmarkService) AddBookmark(ctx context.Context, userID, storyID int) error {
+	ctx, span := tracing.Start(ctx, "BookmarkService.AddBookmark")
+	defer span.End()
+
 	// Validate story exists
 	story, err := s.storyRepo.GetByID(ctx, storyID)
 	if err != nil {
-		slog.Error("failed to check story existence", "error", err, "story_id", storyID)
+		slog.ErrorContext(ctx, "failed to check story existence", "error", err, "story_id", storyID)
 		return errors.New("failed to add bookmark")
 	}
 	if story == nil {
-		return errors.New("story not found")
+		return apperror.NotFound("story_not_found", "story not found")
+	}
+
+	// Bookmarks live in the built-in "reading" list
+	if err := s.listService.EnsureDefaultLists(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "failed to create default reading lists", "error", err, "user_id", userID)
+		return errors.New("failed to add bookmark")
 	}
 
 	err = s.bookmarkRepo.Create(ctx, userID, storyID)
 	if err != nil {
-		slog.Error("failed to create bookmark", "error", err, "user_id", userID, "story_id", storyID)
+		slog.ErrorContext(ctx, "failed to create bookmark", "error", err, "user_id", userID, "story_id", storyID)
 		return errors.New("failed to add bookmark")
 	}
 
-	slog.Info("bookmark added", "user_id", userID, "story_id", storyID)
+	slog.InfoContext(ctx, "bookmark added", "user_id", userID, "story_id", storyID)
 	return nil
 }
 
 func (s *BookmarkService) RemoveBookmark(ctx context.Context, userID, storyID int) error {
+	ctx, span := tracing.Start(ctx, "BookmarkService.RemoveBookmark")
+	defer span.End()
+
 	err := s.bookmarkRepo.Delete(ctx, userID, storyID)
 	if err != nil {
-		slog.Error("failed to remove bookmark", "error", err, "user_id", userID, "story_id", storyID)
+		slog.ErrorContext(ctx, "failed to remove bookmark", "error", err, "user_id", userID, "story_id", storyID)
 		return errors.New("failed to remove bookmark")
 	}
 
-	slog.Info("bookmark removed", "user_id", userID, "story_id", storyID)
+	slog.InfoContext(ctx, "bookmark removed", "user_id", userID, "story_id", storyID)
 	return nil
 }
 
 func (s *BookmarkService) GetUserBookmarks(ctx context.Context, userID, limit, offset int) ([]models.BookmarkWithStory, int64, error) {
+	ctx, span := tracing.Start(ctx, "BookmarkService.GetUserBookmarks")
+	defer span.End()
+
 	bookmarks, total, err := s.bookmarkRepo.GetByUser(ctx, userID, limit, offset)
 	if err != nil {
-		slog.Error("failed to get user bookmarks", "error", err, "user_id", userID)
+		slog.ErrorContext(ctx, "failed to get user bookmarks", "error", err, "user_id", userID)
 		return nil, 0, errors.New("failed to get bookmarks")
 	}
 
-	slog.Debug("fetched user bookmarks", "user_id", userID, "count", len(bookmarks))
+	slog.DebugContext(ctx, "fetched user bookmarks", "user_id", userID, "count", len(bookmarks))
 	return bookmarks, total, nil
 }
 
 func (s *BookmarkService) GetBookmarkStatus(ctx context.Context, userID, storyID int) (bool, int64, error) {
+	ctx, span := tracing.Start(ctx, "BookmarkService.GetBookmarkStatus")
+	defer span.End()
+
 	isBookmarked, err := s.bookmarkRepo.Exists(ctx, userID, storyID)
 	if err != nil {
-		slog.Error("failed to check bookmark status", "error", err, "user_id", userID, "story_id", storyID)
+		slog.ErrorContext(ctx, "failed to check bookmark status", "error", err, "user_id", userID, "story_id", storyID)
 		return false, 0, err
 	}
 
 	totalBookmarks, err := s.bookmarkRepo.CountByStory(ctx, storyID)
 	if err != nil {
-		slog.Error("failed to count bookmarks", "error", err, "story_id", storyID)
+		slog.ErrorContext(ctx, "failed to count bookmarks", "error", err, "story_id", storyID)
 		return false, 0, err
 	}
 
@@ -84,15 +107,18 @@ func (s *BookmarkService) GetBookmarkStatus(ctx context.Context, userID, storyID
 }
 
 func (s *BookmarkService) GetStoryViewStats(ctx context.Context, slug string) (*models.Story, error) {
+	ctx, span := tracing.Start(ctx, "BookmarkService.GetStoryViewStats")
+	defer span.End()
+
 	story, err := s.storyRepo.GetBySlug(ctx, slug)
 	if err != nil {
-		slog.Error("failed to get story for view stats", "error", err, "slug", slug)
+		slog.ErrorContext(ctx, "failed to get story for view stats", "error", err, "slug", slug)
 		return nil, err
 	}
 	if story == nil {
-		return nil, errors.New("story not found")
+		return nil, apperror.NotFound("story_not_found", "story not found")
 	}
 
-	slog.Debug("fetched view stats", "slug", slug, "total_views", story.TotalViews)
+	slog.DebugContext(ctx, "fetched view stats", "slug", slug, "total_views", story.TotalViews)
 	return story, nil
 }
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"web-be/cache"
+	"web-be/models"
+	"web-be/repository"
+)
+
+// How long cached reads may be served. Writes through the services invalidate
+// the affected keys, so these only bound staleness from view counters and
+// from changes made elsewhere, such as another replica's in-process cache.
+const (
+	storyCacheTTL    = 5 * time.Minute
+	chapterCacheTTL  = 10 * time.Minute
+	categoryCacheTTL = time.Hour
+)
+
+// categoryTreeCacheKey holds the GET /categories tree with its story counts
+const categoryTreeCacheKey = "categories:tree"
+
+func storyCacheKey(slug string) string {
+	return "story:" + slug
+}
+
+func chapterCacheKey(storyID, chapterNumber int) string {
+	return fmt.Sprintf("chapter:%d:%d", storyID, chapterNumber)
+}
+
+func categoryCacheKey(slug string) string {
+	return "category:" + slug
+}
+
+// invalidateChapter drops a chapter page, the pages whose prev/next links may
+// name it, and the story, whose chapter count may change. Navigation skips
+// unpublished and trashed chapters, so those pages can be further away than
+// the adjacent numbers; call it after the write.
+func invalidateChapter(ctx context.Context, c cache.Cache, chapterRepo *repository.ChapterRepository, story *models.Story, chapterNumber int) {
+	numbers, err := chapterRepo.GetNavNeighbourhood(ctx, story.ID, chapterNumber)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to find chapters linking to a changed chapter", "error", err, "story_id", story.ID, "chapter_number", chapterNumber)
+		numbers = []int{chapterNumber - 1, chapterNumber + 1}
+	}
+
+	keys := []string{storyCacheKey(story.Slug), chapterCacheKey(story.ID, chapterNumber)}
+	for _, n := range numbers {
+		if n != chapterNumber {
+			keys = append(keys, chapterCacheKey(story.ID, n))
+		}
+	}
+	cache.Invalidate(ctx, c, keys...)
+}
+
+// invalidateStories drops the cached details of stories showing a tag or
+// category that was just renamed, merged or deleted
+func invalidateStories(ctx context.Context, c cache.Cache, slugs []string) {
+	keys := make([]string, len(slugs))
+	for i, slug := range slugs {
+		keys[i] = storyCacheKey(slug)
+	}
+	cache.Invalidate(ctx, c, keys...)
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"web-be/apperror"
+	"web-be/cache"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+	"web-be/utils"
+)
+
+type CategoryService struct {
+	categoryRepo *repository.CategoryRepository
+	cache        cache.Cache
+}
+
+func NewCategoryService(categoryRepo *repository.CategoryRepository, cache cache.Cache) *CategoryService {
+	return &CategoryService{categoryRepo: categoryRepo, cache: cache}
+}
+
+// GetTree returns top-level categories in display order, each with its subcategories
+func (s *CategoryService) GetTree(ctx context.Context) ([]models.CategoryNode, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetTree")
+	defer span.End()
+
+	return cache.Fetch(ctx, s.cache, categoryTreeCacheKey, categoryCacheTTL, s.loadTree)
+}
+
+func (s *CategoryService) loadTree(ctx context.Context) ([]models.CategoryNode, error) {
+	categories, err := s.categoryRepo.GetAllWithCounts(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get categories", "error", err)
+		return nil, errors.New("failed to get categories")
+	}
+
+	children := make(map[int][]models.CategoryNode)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	tree := []models.CategoryNode{}
+	for _, category := range categories {
+		if category.ParentID == nil {
+			category.Children = children[category.ID]
+			tree = append(tree, category)
+		}
+	}
+	return tree, nil
+}
+
+// GetBySlug resolves a category slug, following redirects left by renames and merges
+func (s *CategoryService) GetBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.GetBySlug")
+	defer span.End()
+
+	return cache.Fetch(ctx, s.cache, categoryCacheKey(slug), categoryCacheTTL, func(ctx context.Context) (*models.Category, error) {
+		category, err := s.categoryRepo.GetBySlug(ctx, slug)
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			category, err = s.categoryRepo.GetByOldSlug(ctx, slug)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if category == nil {
+			return nil, apperror.NotFound("category_not_found", "category not found")
+		}
+		return category, nil
+	})
+}
+
+func (s *CategoryService) Create(ctx context.Context, req *dto.CreateCategoryRequest) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.Create")
+	defer span.End()
+
+	slug := utils.GenerateSlug(req.Name)
+
+	existing, _ := s.categoryRepo.GetBySlug(ctx, slug)
+	if existing != nil {
+		return nil, apperror.Conflict("category_exists", "category already exists")
+	}
+
+	category := &models.Category{
+		Name:         req.Name,
+		Slug:         slug,
+		Description:  req.Description,
+		ParentID:     req.ParentID,
+		DisplayOrder: req.DisplayOrder,
+	}
+	if category.ParentID != nil {
+		if err := s.checkParent(ctx, category, *category.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.categoryRepo.Create(ctx, category)
+	if err != nil {
+		return nil, errors.New("failed to create category")
+	}
+
+	cache.Invalidate(ctx, s.cache, categoryTreeCacheKey)
+	slog.InfoContext(ctx, "category created", "id", category.ID, "slug", category.Slug)
+	return category, nil
+}
+
+// Update renames, re-parents or reorders a category. A rename changes the slug
+// and keeps the old one as a redirect.
+func (s *CategoryService) Update(ctx context.Context, id int, req *dto.UpdateCategoryRequest) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.Update")
+	defer span.End()
+
+	category, err := s.getByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	oldSlug := category.Slug
+
+	if req.Name != nil {
+		slug := utils.GenerateSlug(*req.Name)
+		if slug != category.Slug {
+			existing, _ := s.categoryRepo.GetBySlug(ctx, slug)
+			if existing != nil {
+				return nil, apperror.Conflict("category_exists", "category already exists")
+			}
+		}
+		category.Name = *req.Name
+		category.Slug = slug
+	}
+	if req.Description != nil {
+		category.Description = req.Description
+	}
+	if req.DisplayOrder != nil {
+		category.DisplayOrder = *req.DisplayOrder
+	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			category.ParentID = nil
+		} else {
+			if err := s.checkParent(ctx, category, *req.ParentID); err != nil {
+				return nil, err
+			}
+			category.ParentID = req.ParentID
+		}
+	}
+
+	if err := s.categoryRepo.Update(ctx, category, oldSlug); err != nil {
+		slog.ErrorContext(ctx, "failed to update category", "error", err, "category_id", id)
+		return nil, errors.New("failed to update category")
+	}
+
+	cache.Invalidate(ctx, s.cache, categoryTreeCacheKey, categoryCacheKey(oldSlug), categoryCacheKey(category.Slug))
+	s.invalidateStories(ctx, category.ID)
+	slog.InfoContext(ctx, "category updated", "id", category.ID, "slug", category.Slug, "old_slug", oldSlug)
+	return category, nil
+}
+
+// Delete removes a category, optionally moving its stories to another one first
+func (s *CategoryService) Delete(ctx context.Context, id int, req *dto.DeleteCategoryRequest) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.Delete")
+	defer span.End()
+
+	category, err := s.getByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if req.ReassignTo != nil {
+		if *req.ReassignTo == id {
+			return apperror.InvalidField("invalid_reassign_target", "reassign_to", "cannot reassign stories to the category being deleted")
+		}
+		if _, err := s.getByID(ctx, *req.ReassignTo); err != nil {
+			return err
+		}
+	}
+
+	// The story links are gone once the category is deleted, so list them first
+	slugs, err := s.categoryRepo.GetStorySlugs(ctx, id)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list category stories", "error", err, "category_id", id)
+		return errors.New("failed to delete category")
+	}
+
+	if err := s.categoryRepo.Delete(ctx, id, req.ReassignTo); err != nil {
+		slog.ErrorContext(ctx, "failed to delete category", "error", err, "category_id", id)
+		return errors.New("failed to delete category")
+	}
+
+	cache.Invalidate(ctx, s.cache, categoryTreeCacheKey, categoryCacheKey(category.Slug))
+	invalidateStories(ctx, s.cache, slugs)
+	slog.InfoContext(ctx, "category deleted", "id", id, "reassign_to", req.ReassignTo)
+	return nil
+}
+
+// Merge folds one category into another; the merged category's slug redirects
+// to the target and its subcategories move under it
+func (s *CategoryService) Merge(ctx context.Context, id int, req *dto.MergeCategoryRequest) (*models.Category, error) {
+	ctx, span := tracing.Start(ctx, "CategoryService.Merge")
+	defer span.End()
+
+	if id == req.IntoID {
+		return nil, apperror.InvalidField("merge_into_self", "into_id", "cannot merge a category into itself")
+	}
+	source, err := s.getByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getByID(ctx, req.IntoID)
+	if err != nil {
+		return nil, err
+	}
+	if target.ParentID != nil && *target.ParentID == source.ID {
+		return nil, apperror.InvalidField("category_cycle", "into_id", "cannot merge a category into one of its subcategories")
+	}
+	if target.ParentID != nil {
+		count, err := s.categoryRepo.CountChildren(ctx, source.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, apperror.InvalidField("category_too_deep", "into_id", "categories with subcategories can only be merged into a top-level category")
+		}
+	}
+
+	if err := s.categoryRepo.Merge(ctx, source, target.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to merge categories", "error", err, "source_id", source.ID, "target_id", target.ID)
+		return nil, errors.New("failed to merge categories")
+	}
+
+	cache.Invalidate(ctx, s.cache, categoryTreeCacheKey, categoryCacheKey(source.Slug), categoryCacheKey(target.Slug))
+	// The source's stories have moved to the target, so this covers both
+	s.invalidateStories(ctx, target.ID)
+	slog.InfoContext(ctx, "categories merged", "source", source.Slug, "target", target.Slug)
+	return target, nil
+}
+
+// Reorder sets the display order of categories to their position in the list
+func (s *CategoryService) Reorder(ctx context.Context, req *dto.ReorderCategoriesRequest) error {
+	ctx, span := tracing.Start(ctx, "CategoryService.Reorder")
+	defer span.End()
+
+	if err := s.categoryRepo.Reorder(ctx, req.CategoryIDs); err != nil {
+		slog.ErrorContext(ctx, "failed to reorder categories", "error", err)
+		return errors.New("failed to reorder categories")
+	}
+	cache.Invalidate(ctx, s.cache, categoryTreeCacheKey)
+	return nil
+}
+
+// invalidateStories drops the cached details of every story in the category
+func (s *CategoryService) invalidateStories(ctx context.Context, id int) {
+	slugs, err := s.categoryRepo.GetStorySlugs(ctx, id)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list category stories for cache invalidation", "error", err, "category_id", id)
+		return
+	}
+	invalidateStories(ctx, s.cache, slugs)
+}
+
+func (s *CategoryService) getByID(ctx context.Context, id int) (*models.Category, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, apperror.NotFound("category_not_found", "category not found")
+	}
+	return category, nil
+}
+
+// checkParent keeps the tree two levels deep: a parent must be top-level and a
+// category that has subcategories cannot become one itself
+func (s *CategoryService) checkParent(ctx context.Context, category *models.Category, parentID int) error {
+	if parentID == category.ID {
+		return apperror.InvalidField("category_cycle", "parent_id", "a category cannot be its own parent")
+	}
+	parent, err := s.getByID(ctx, parentID)
+	if err != nil {
+		return apperror.InvalidField("parent_category_not_found", "parent_id", "parent category not found")
+	}
+	if parent.ParentID != nil {
+		return apperror.InvalidField("category_too_deep", "parent_id", "subcategories cannot have subcategories")
+	}
+	if category.ID != 0 {
+		count, err := s.categoryRepo.CountChildren(ctx, category.ID)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return apperror.InvalidField("category_too_deep", "parent_id", "a category with subcategories cannot be moved under another category")
+		}
+	}
+	return nil
+}
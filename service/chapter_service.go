@@ -3,38 +3,61 @@ package service
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"time"
 
+	"web-be/apperror"
+	"web-be/cache"
 	"web-be/dto"
+	"web-be/metrics"
 	"web-be/models"
 	"web-be/repository"
+	"web-be/tracing"
 	"web-be/utils"
 )
 
 type ChapterService struct {
-	chapterRepo *repository.ChapterRepository
-	storyRepo   *repository.StoryRepository
+	chapterRepo  *repository.ChapterRepository
+	storyRepo    *repository.StoryRepository
+	progressRepo *repository.ReadingProgressRepository
+	historyRepo  *repository.ReadingHistoryRepository
+	statsRepo    *repository.ReadingStatsRepository
+	cache        cache.Cache
 }
 
-func NewChapterService(chapterRepo *repository.ChapterRepository, storyRepo *repository.StoryRepository) *ChapterService {
+func NewChapterService(
+	chapterRepo *repository.ChapterRepository,
+	storyRepo *repository.StoryRepository,
+	progressRepo *repository.ReadingProgressRepository,
+	historyRepo *repository.ReadingHistoryRepository,
+	statsRepo *repository.ReadingStatsRepository,
+	cache cache.Cache,
+) *ChapterService {
 	return &ChapterService{
-		chapterRepo: chapterRepo,
-		storyRepo:   storyRepo,
+		chapterRepo:  chapterRepo,
+		storyRepo:    storyRepo,
+		progressRepo: progressRepo,
+		historyRepo:  historyRepo,
+		statsRepo:    statsRepo,
+		cache:        cache,
 	}
 }
 
 func (s *ChapterService) Create(ctx context.Context, storySlug string, userID int, userRole string, req *dto.CreateChapterRequest) (*models.Chapter, error) {
+	ctx, span := tracing.Start(ctx, "ChapterService.Create")
+	defer span.End()
+
 	story, err := s.storyRepo.GetBySlug(ctx, storySlug)
 	if err != nil {
 		return nil, err
 	}
 	if story == nil {
-		return nil, errors.New("story not found")
+		return nil, apperror.NotFound("story_not_found", "story not found")
 	}
 
 	// Check permission
 	if userRole != "admin" && (story.AuthorID == nil || *story.AuthorID != userID) {
-		return nil, errors.New("you don't have permission to add chapters to this story")
+		return nil, apperror.Forbidden("forbidden", "you don't have permission to add chapters to this story")
 	}
 
 	// Get next chapter number
@@ -44,7 +67,11 @@ func (s *ChapterService) Create(ctx context.Context, storySlug string, userID in
 	}
 	nextNum := maxNum + 1
 
-	slug := utils.GenerateSlug(req.Title)
+	slug, err := s.uniqueSlug(ctx, story.ID, req.Title, 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate chapter slug", "error", err, "story_id", story.ID)
+		return nil, errors.New("failed to create chapter")
+	}
 	wordCount := utils.CountWords(req.Content)
 
 	var publishedAt *time.Time
@@ -68,37 +95,71 @@ func (s *ChapterService) Create(ctx context.Context, storySlug string, userID in
 	if err != nil {
 		return nil, errors.New("failed to create chapter")
 	}
+	if chapter.IsPublished {
+		metrics.ChaptersPublished.Inc()
+	}
 
 	// Update story chapter count
 	_ = s.storyRepo.UpdateChapterCount(ctx, story.ID)
+	invalidateChapter(ctx, s.cache, s.chapterRepo, story, chapter.ChapterNumber)
 
 	return chapter, nil
 }
 
-func (s *ChapterService) GetByStoryAndNumber(ctx context.Context, storySlug string, chapterNum int) (*dto.ChapterResponse, error) {
+// GetByStoryAndNumber returns a chapter for reading. When userID is non-zero
+// the reader's saved position is included and, if record is set, the read is
+// recorded; support impersonating a reader must leave their progress alone.
+// The page itself is cached per chapter; the story lookup is not, so a
+// trashed story's chapters stop being served at once.
+func (s *ChapterService) GetByStoryAndNumber(ctx context.Context, storySlug string, chapterNum int, userID int, record bool) (*dto.ChapterResponse, error) {
+	ctx, span := tracing.Start(ctx, "ChapterService.GetByStoryAndNumber")
+	defer span.End()
+
 	story, err := s.storyRepo.GetBySlug(ctx, storySlug)
 	if err != nil {
 		return nil, err
 	}
 	if story == nil {
-		return nil, errors.New("story not found")
+		return nil, apperror.NotFound("story_not_found", "story not found")
 	}
 
-	chapter, err := s.chapterRepo.GetByStoryAndNumber(ctx, story.ID, chapterNum)
+	response, err := cache.Fetch(ctx, s.cache, chapterCacheKey(story.ID, chapterNum), chapterCacheTTL, func(ctx context.Context) (*dto.ChapterResponse, error) {
+		return s.chapterPage(ctx, story.ID, chapterNum)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if chapter == nil {
-		return nil, errors.New("chapter not found")
-	}
 
 	// Increment views
-	_ = s.chapterRepo.IncrementViews(ctx, chapter.ID)
+	_ = s.chapterRepo.IncrementViews(ctx, response.ID)
 	_ = s.storyRepo.IncrementViews(ctx, story.ID)
+	metrics.ViewsRecorded.WithLabelValues("chapter").Inc()
+
+	if userID != 0 && response.IsPublished {
+		if record {
+			response.Progress = s.recordRead(ctx, userID, response)
+		} else {
+			response.Progress = s.savedProgress(ctx, userID, response.ID)
+		}
+	}
+
+	return response, nil
+}
+
+// chapterPage loads a chapter with its prev/next navigation, everything in a
+// chapter response that is the same for every reader
+func (s *ChapterService) chapterPage(ctx context.Context, storyID, chapterNum int) (*dto.ChapterResponse, error) {
+	chapter, err := s.chapterRepo.GetByStoryAndNumber(ctx, storyID, chapterNum)
+	if err != nil {
+		return nil, err
+	}
+	if chapter == nil {
+		return nil, apperror.NotFound("chapter_not_found", "chapter not found")
+	}
 
 	// Get navigation
-	prevChapter, _ := s.chapterRepo.GetPrevChapter(ctx, story.ID, chapterNum)
-	nextChapter, _ := s.chapterRepo.GetNextChapter(ctx, story.ID, chapterNum)
+	prevChapter, _ := s.chapterRepo.GetPrevChapter(ctx, storyID, chapterNum)
+	nextChapter, _ := s.chapterRepo.GetNextChapter(ctx, storyID, chapterNum)
 
 	response := &dto.ChapterResponse{
 		ID:            chapter.ID,
@@ -138,13 +199,160 @@ func (s *ChapterService) GetByStoryAndNumber(ctx context.Context, storySlug stri
 	return response, nil
 }
 
-func (s *ChapterService) GetListByStory(ctx context.Context, storySlug string, limit, offset int) ([]dto.ChapterListResponse, int64, error) {
+// ResolveSlug returns the number of the chapter with chapterSlug, current or
+// former, in a story
+func (s *ChapterService) ResolveSlug(ctx context.Context, storySlug, chapterSlug string) (int, error) {
+	ctx, span := tracing.Start(ctx, "ChapterService.ResolveSlug")
+	defer span.End()
+
+	story, err := s.storyRepo.GetBySlug(ctx, storySlug)
+	if err != nil {
+		return 0, err
+	}
+	if story == nil {
+		return 0, apperror.NotFound("story_not_found", "story not found")
+	}
+
+	chapter, err := s.chapterRepo.GetByStoryAndSlug(ctx, story.ID, chapterSlug)
+	if err != nil {
+		return 0, err
+	}
+	if chapter == nil {
+		chapter, err = s.chapterRepo.GetByOldSlug(ctx, story.ID, chapterSlug)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if chapter == nil {
+		return 0, apperror.NotFound("chapter_not_found", "chapter not found")
+	}
+	return chapter.ChapterNumber, nil
+}
+
+// recordRead marks the chapter as opened and moves the story's history to it.
+// Failures are logged but never stop the chapter from being served.
+func (s *ChapterService) recordRead(ctx context.Context, userID int, chapter *dto.ChapterResponse) *dto.ReadingProgressResponse {
+	read, err := s.progressRepo.RecordOpen(ctx, userID, chapter.StoryID, chapter.ID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to record chapter read", "error", err, "user_id", userID, "chapter_id", chapter.ID)
+		return nil
+	}
+
+	history := &models.ReadingHistory{
+		UserID:        userID,
+		StoryID:       chapter.StoryID,
+		LastChapterID: &chapter.ID,
+	}
+	if err := s.historyRepo.Upsert(ctx, history); err != nil {
+		slog.WarnContext(ctx, "failed to update reading history", "error", err, "user_id", userID, "story_id", chapter.StoryID)
+	}
+
+	if err := s.statsRepo.RecordEvent(ctx, userID, chapter.StoryID, chapter.ID, chapter.WordCount); err != nil {
+		slog.WarnContext(ctx, "failed to record reading event", "error", err, "user_id", userID, "chapter_id", chapter.ID)
+	}
+
+	return toReadingProgressResponse(read)
+}
+
+// savedProgress returns the reader's position in a chapter without touching it
+func (s *ChapterService) savedProgress(ctx context.Context, userID, chapterID int) *dto.ReadingProgressResponse {
+	reads, err := s.progressRepo.GetByChapters(ctx, userID, []int{chapterID})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get reading progress", "error", err, "user_id", userID, "chapter_id", chapterID)
+		return nil
+	}
+	read, ok := reads[chapterID]
+	if !ok {
+		return nil
+	}
+	return toReadingProgressResponse(&read)
+}
+
+// SaveProgress stores the reader's position inside a chapter
+func (s *ChapterService) SaveProgress(ctx context.Context, storySlug string, chapterNum int, userID int, req *dto.UpdateReadingProgressRequest) (*dto.ReadingProgressResponse, error) {
+	ctx, span := tracing.Start(ctx, "ChapterService.SaveProgress")
+	defer span.End()
+
+	story, err := s.storyRepo.GetBySlug(ctx, storySlug)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil {
+		return nil, apperror.NotFound("story_not_found", "story not found")
+	}
+
+	chapter, err := s.chapterRepo.GetByStoryAndNumber(ctx, story.ID, chapterNum)
+	if err != nil {
+		return nil, err
+	}
+	if chapter == nil || !chapter.IsPublished {
+		return nil, apperror.NotFound("chapter_not_found", "chapter not found")
+	}
+
+	read := &models.ChapterRead{
+		UserID:          userID,
+		ChapterID:       chapter.ID,
+		StoryID:         story.ID,
+		ParagraphIndex:  req.ParagraphIndex,
+		ScrollOffset:    req.ScrollOffset,
+		ProgressPercent: req.ProgressPercent,
+	}
+	if err := s.progressRepo.SavePosition(ctx, read); err != nil {
+		slog.ErrorContext(ctx, "failed to save reading progress", "error", err, "user_id", userID, "chapter_id", chapter.ID)
+		return nil, errors.New("failed to save reading progress")
+	}
+
+	history := &models.ReadingHistory{
+		UserID:        userID,
+		StoryID:       story.ID,
+		LastChapterID: &chapter.ID,
+	}
+	if err := s.historyRepo.Upsert(ctx, history); err != nil {
+		slog.WarnContext(ctx, "failed to update reading history", "error", err, "user_id", userID, "story_id", story.ID)
+	}
+
+	return toReadingProgressResponse(read), nil
+}
+
+func (s *ChapterService) GetContinueReading(ctx context.Context, userID, limit, offset int) ([]models.ContinueReadingItem, int64, error) {
+	ctx, span := tracing.Start(ctx, "ChapterService.GetContinueReading")
+	defer span.End()
+
+	items, total, err := s.progressRepo.GetContinueReading(ctx, userID, limit, offset)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get continue reading", "error", err, "user_id", userID)
+		return nil, 0, errors.New("failed to get continue reading")
+	}
+	return items, total, nil
+}
+
+func toReadingProgressResponse(read *models.ChapterRead) *dto.ReadingProgressResponse {
+	response := &dto.ReadingProgressResponse{
+		ParagraphIndex:  read.ParagraphIndex,
+		ScrollOffset:    read.ScrollOffset,
+		ProgressPercent: read.ProgressPercent,
+		IsRead:          read.IsRead(),
+		LastReadAt:      read.LastReadAt.Format(time.RFC3339),
+	}
+	if read.CompletedAt != nil {
+		t := read.CompletedAt.Format(time.RFC3339)
+		response.CompletedAt = &t
+	}
+	return response
+}
+
+// GetListByStory lists published chapters; when userID is non-zero each one
+// carries the reader's read marker
+func (s *ChapterService) GetListByStory(ctx context.Context, storySlug string, userID int, limit, offset int) ([]dto.ChapterListResponse, int64, error) {
+	ctx, span := tracing.Start(ctx, "ChapterService.GetListByStory")
+	defer span.End()
+
 	story, err := s.storyRepo.GetBySlug(ctx, storySlug)
 	if err != nil {
 		return nil, 0, err
 	}
 	if story == nil {
-		return nil, 0, errors.New("story not found")
+		return nil, 0, apperror.NotFound("story_not_found", "story not found")
 	}
 
 	chapters, total, err := s.chapterRepo.GetListByStory(ctx, story.ID, limit, offset)
@@ -152,6 +360,18 @@ func (s *ChapterService) GetListByStory(ctx context.Context, storySlug string, l
 		return nil, 0, err
 	}
 
+	var reads map[int]models.ChapterRead
+	if userID != 0 {
+		chapterIDs := make([]int, len(chapters))
+		for i, ch := range chapters {
+			chapterIDs[i] = ch.ID
+		}
+		reads, err = s.progressRepo.GetByChapters(ctx, userID, chapterIDs)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to get read markers", "error", err, "user_id", userID, "story_id", story.ID)
+		}
+	}
+
 	var responses []dto.ChapterListResponse
 	for _, ch := range chapters {
 		response := dto.ChapterListResponse{
@@ -169,6 +389,13 @@ func (s *ChapterService) GetListByStory(ctx context.Context, storySlug string, l
 			t := ch.PublishedAt.Format(time.RFC3339)
 			response.PublishedAt = &t
 		}
+		if userID != 0 && reads != nil {
+			read, ok := reads[ch.ID]
+			isRead := ok && read.IsRead()
+			percent := read.ProgressPercent
+			response.IsRead = &isRead
+			response.ProgressPercent = &percent
+		}
 		responses = append(responses, response)
 	}
 
@@ -176,16 +403,19 @@ func (s *ChapterService) GetListByStory(ctx context.Context, storySlug string, l
 }
 
 func (s *ChapterService) Update(ctx context.Context, storySlug string, chapterNum int, userID int, userRole string, req *dto.UpdateChapterRequest) (*models.Chapter, error) {
+	ctx, span := tracing.Start(ctx, "ChapterService.Update")
+	defer span.End()
+
 	story, err := s.storyRepo.GetBySlug(ctx, storySlug)
 	if err != nil {
 		return nil, err
 	}
 	if story == nil {
-		return nil, errors.New("story not found")
+		return nil, apperror.NotFound("story_not_found", "story not found")
 	}
 
 	if userRole != "admin" && (story.AuthorID == nil || *story.AuthorID != userID) {
-		return nil, errors.New("you don't have permission to edit this chapter")
+		return nil, apperror.Forbidden("forbidden", "you don't have permission to edit this chapter")
 	}
 
 	chapter, err := s.chapterRepo.GetByStoryAndNumber(ctx, story.ID, chapterNum)
@@ -193,17 +423,25 @@ func (s *ChapterService) Update(ctx context.Context, storySlug string, chapterNu
 		return nil, err
 	}
 	if chapter == nil {
-		return nil, errors.New("chapter not found")
+		return nil, apperror.NotFound("chapter_not_found", "chapter not found")
 	}
 
+	oldSlug := chapter.Slug
 	if req.Title != nil {
 		chapter.Title = *req.Title
-		chapter.Slug = utils.GenerateSlug(*req.Title)
+		if !req.KeepSlug {
+			chapter.Slug, err = s.uniqueSlug(ctx, story.ID, *req.Title, chapter.ID)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to generate chapter slug", "error", err, "chapter_id", chapter.ID)
+				return nil, errors.New("failed to update chapter")
+			}
+		}
 	}
 	if req.Content != nil {
 		chapter.Content = *req.Content
 		chapter.WordCount = utils.CountWords(*req.Content)
 	}
+	newlyPublished := req.IsPublished != nil && *req.IsPublished && !chapter.IsPublished
 	if req.IsPublished != nil {
 		chapter.IsPublished = *req.IsPublished
 		if *req.IsPublished && chapter.PublishedAt == nil {
@@ -212,27 +450,34 @@ func (s *ChapterService) Update(ctx context.Context, storySlug string, chapterNu
 		}
 	}
 
-	err = s.chapterRepo.Update(ctx, chapter)
+	err = s.chapterRepo.Update(ctx, chapter, oldSlug)
 	if err != nil {
 		return nil, errors.New("failed to update chapter")
 	}
+	if newlyPublished {
+		metrics.ChaptersPublished.Inc()
+	}
 
 	_ = s.storyRepo.UpdateChapterCount(ctx, story.ID)
+	invalidateChapter(ctx, s.cache, s.chapterRepo, story, chapterNum)
 
 	return chapter, nil
 }
 
 func (s *ChapterService) Delete(ctx context.Context, storySlug string, chapterNum int, userID int, userRole string) error {
+	ctx, span := tracing.Start(ctx, "ChapterService.Delete")
+	defer span.End()
+
 	story, err := s.storyRepo.GetBySlug(ctx, storySlug)
 	if err != nil {
 		return err
 	}
 	if story == nil {
-		return errors.New("story not found")
+		return apperror.NotFound("story_not_found", "story not found")
 	}
 
 	if userRole != "admin" && (story.AuthorID == nil || *story.AuthorID != userID) {
-		return errors.New("you don't have permission to delete this chapter")
+		return apperror.Forbidden("forbidden", "you don't have permission to delete this chapter")
 	}
 
 	chapter, err := s.chapterRepo.GetByStoryAndNumber(ctx, story.ID, chapterNum)
@@ -240,7 +485,7 @@ func (s *ChapterService) Delete(ctx context.Context, storySlug string, chapterNu
 		return err
 	}
 	if chapter == nil {
-		return errors.New("chapter not found")
+		return apperror.NotFound("chapter_not_found", "chapter not found")
 	}
 
 	err = s.chapterRepo.Delete(ctx, chapter.ID)
@@ -249,6 +494,15 @@ func (s *ChapterService) Delete(ctx context.Context, storySlug string, chapterNu
 	}
 
 	_ = s.storyRepo.UpdateChapterCount(ctx, story.ID)
+	invalidateChapter(ctx, s.cache, s.chapterRepo, story, chapterNum)
 
 	return nil
 }
+
+// uniqueSlug derives a chapter slug from title that is not used, now or
+// formerly, by another chapter of the story
+func (s *ChapterService) uniqueSlug(ctx context.Context, storyID int, title string, chapterID int) (string, error) {
+	return uniqueSlug(utils.GenerateSlug(title), "chapter", func(slug string) (bool, error) {
+		return s.chapterRepo.SlugTaken(ctx, storyID, slug, chapterID)
+	})
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+)
+
+const defaultFeedLimit = 20
+
+type FollowService struct {
+	followRepo  *repository.FollowRepository
+	userRepo    *repository.UserRepository
+	profileRepo *repository.ProfileRepository
+}
+
+func NewFollowService(
+	followRepo *repository.FollowRepository,
+	userRepo *repository.UserRepository,
+	profileRepo *repository.ProfileRepository,
+) *FollowService {
+	return &FollowService{
+		followRepo:  followRepo,
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+	}
+}
+
+func (s *FollowService) Follow(ctx context.Context, followerID int, username string) (*dto.FollowStatusResponse, error) {
+	ctx, span := tracing.Start(ctx, "FollowService.Follow")
+	defer span.End()
+
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == followerID {
+		return nil, apperror.Validation("cannot_follow_self", "you cannot follow yourself")
+	}
+
+	if err := s.followRepo.Create(ctx, followerID, user.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to follow user", "error", err, "follower_id", followerID, "followee_id", user.ID)
+		return nil, errors.New("failed to follow user")
+	}
+
+	slog.InfoContext(ctx, "user followed", "follower_id", followerID, "followee_id", user.ID)
+	return s.status(ctx, followerID, user.ID)
+}
+
+func (s *FollowService) Unfollow(ctx context.Context, followerID int, username string) (*dto.FollowStatusResponse, error) {
+	ctx, span := tracing.Start(ctx, "FollowService.Unfollow")
+	defer span.End()
+
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.followRepo.Delete(ctx, followerID, user.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to unfollow user", "error", err, "follower_id", followerID, "followee_id", user.ID)
+		return nil, errors.New("failed to unfollow user")
+	}
+
+	slog.InfoContext(ctx, "user unfollowed", "follower_id", followerID, "followee_id", user.ID)
+	return s.status(ctx, followerID, user.ID)
+}
+
+func (s *FollowService) GetStatus(ctx context.Context, followerID int, username string) (*dto.FollowStatusResponse, error) {
+	ctx, span := tracing.Start(ctx, "FollowService.GetStatus")
+	defer span.End()
+
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	return s.status(ctx, followerID, user.ID)
+}
+
+// GetFollowers lists who follows a user; only the user sees it unless their
+// profile shows followers
+func (s *FollowService) GetFollowers(ctx context.Context, viewerID int, username string, limit, offset int) ([]models.FollowUser, int64, error) {
+	ctx, span := tracing.Start(ctx, "FollowService.GetFollowers")
+	defer span.End()
+
+	user, err := s.getVisibleFollows(ctx, viewerID, username)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.followRepo.GetFollowers(ctx, user.ID, limit, offset)
+}
+
+// GetFollowing lists who a user follows, under the same setting as GetFollowers
+func (s *FollowService) GetFollowing(ctx context.Context, viewerID int, username string, limit, offset int) ([]models.FollowUser, int64, error) {
+	ctx, span := tracing.Start(ctx, "FollowService.GetFollowing")
+	defer span.End()
+
+	user, err := s.getVisibleFollows(ctx, viewerID, username)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.followRepo.GetFollowing(ctx, user.ID, limit, offset)
+}
+
+// GetFeed returns one page of the reader's activity feed. The cursor is opaque
+// to clients; pass back next_cursor to get the following page.
+func (s *FollowService) GetFeed(ctx context.Context, userID int, req *dto.FeedRequest) (*dto.FeedResponse, error) {
+	ctx, span := tracing.Start(ctx, "FollowService.GetFeed")
+	defer span.End()
+
+	limit := req.Limit
+	if limit < 1 {
+		limit = defaultFeedLimit
+	}
+
+	var cursor *repository.FeedCursor
+	if req.Cursor != "" {
+		c, err := decodeFeedCursor(req.Cursor)
+		if err != nil {
+			return nil, apperror.InvalidField("invalid_cursor", "cursor", "invalid cursor")
+		}
+		cursor = c
+	}
+
+	// Fetch one extra row to know whether another page exists
+	items, err := s.followRepo.GetFeed(ctx, userID, cursor, limit+1)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get feed", "error", err, "user_id", userID)
+		return nil, errors.New("failed to get feed")
+	}
+
+	response := &dto.FeedResponse{Items: items}
+	if len(items) > limit {
+		response.Items = items[:limit]
+		last := response.Items[limit-1]
+		response.NextCursor = encodeFeedCursor(repository.FeedCursor{
+			OccurredAt: last.OccurredAt,
+			Type:       last.Type,
+			ItemID:     last.ItemID,
+		})
+	}
+	if response.Items == nil {
+		response.Items = []models.FeedItem{}
+	}
+
+	return response, nil
+}
+
+func (s *FollowService) getUser(ctx context.Context, username string) (*models.User, error) {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, apperror.NotFound("user_not_found", "user not found")
+	}
+	return user, nil
+}
+
+// getVisibleFollows loads a user whose follow lists the viewer may see
+func (s *FollowService) getVisibleFollows(ctx context.Context, viewerID int, username string) (*models.User, error) {
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if viewerID == user.ID {
+		return user, nil
+	}
+
+	profile, err := s.profileRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get profile", "error", err, "user_id", user.ID)
+		return nil, err
+	}
+	if !profile.ShowFollowers {
+		return nil, apperror.Forbidden("followers_hidden", "this user keeps their followers private")
+	}
+	return user, nil
+}
+
+func (s *FollowService) status(ctx context.Context, followerID, followeeID int) (*dto.FollowStatusResponse, error) {
+	isFollowing, err := s.followRepo.Exists(ctx, followerID, followeeID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.profileRepo.CountFollowers(ctx, followeeID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FollowStatusResponse{IsFollowing: isFollowing, FollowerCount: count}, nil
+}
+
+func encodeFeedCursor(c repository.FeedCursor) string {
+	raw := fmt.Sprintf("%d|%s|%d", c.OccurredAt.UnixMicro(), c.Type, c.ItemID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFeedCursor(s string) (*repository.FeedCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed cursor")
+	}
+	micros, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.FeedCursor{
+		OccurredAt: time.UnixMicro(micros).UTC(),
+		Type:       parts[1],
+		ItemID:     id,
+	}, nil
+}
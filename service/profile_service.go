@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+)
+
+type ProfileService struct {
+	userRepo    *repository.UserRepository
+	profileRepo *repository.ProfileRepository
+	storyRepo   *repository.StoryRepository
+}
+
+func NewProfileService(
+	userRepo *repository.UserRepository,
+	profileRepo *repository.ProfileRepository,
+	storyRepo *repository.StoryRepository,
+) *ProfileService {
+	return &ProfileService{
+		userRepo:    userRepo,
+		profileRepo: profileRepo,
+		storyRepo:   storyRepo,
+	}
+}
+
+// GetPublicProfile builds an author page; viewerID is 0 for anonymous readers.
+// Authors always see their own page in full.
+func (s *ProfileService) GetPublicProfile(ctx context.Context, username string, viewerID int) (*dto.PublicProfileResponse, error) {
+	ctx, span := tracing.Start(ctx, "ProfileService.GetPublicProfile")
+	defer span.End()
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get user for profile", "error", err, "username", username)
+		return nil, err
+	}
+	if user == nil {
+		return nil, apperror.NotFound("user_not_found", "user not found")
+	}
+
+	profile, err := s.profileRepo.GetByUserID(ctx, user.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get profile", "error", err, "user_id", user.ID)
+		return nil, err
+	}
+	isOwner := viewerID == user.ID
+
+	response := &dto.PublicProfileResponse{
+		Username:  user.Username,
+		FullName:  user.FullName,
+		AvatarURL: user.AvatarURL,
+		Bio:       profile.Bio,
+	}
+
+	if isOwner || profile.ShowSocialLinks {
+		response.SocialLinks = profile.SocialLinks
+	}
+	if isOwner || profile.ShowJoinDate {
+		response.JoinedAt = &user.CreatedAt
+	}
+	if isOwner || profile.ShowFollowers {
+		count, err := s.profileRepo.CountFollowers(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		response.FollowerCount = &count
+	}
+	if isOwner || profile.ShowStats {
+		stats, err := s.profileRepo.GetAuthorStats(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		response.Stats = stats
+	}
+
+	return response, nil
+}
+
+func (s *ProfileService) GetAuthorStories(ctx context.Context, username string, limit, offset int) ([]models.Story, int64, error) {
+	ctx, span := tracing.Start(ctx, "ProfileService.GetAuthorStories")
+	defer span.End()
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, 0, err
+	}
+	if user == nil {
+		return nil, 0, apperror.NotFound("user_not_found", "user not found")
+	}
+
+	stories, total, err := s.storyRepo.GetPublishedByAuthor(ctx, user.ID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range stories {
+		categories, _ := s.storyRepo.GetCategories(ctx, stories[i].ID)
+		stories[i].Categories = categories
+	}
+
+	return stories, total, nil
+}
+
+func (s *ProfileService) GetSettings(ctx context.Context, userID int) (*models.UserProfile, error) {
+	ctx, span := tracing.Start(ctx, "ProfileService.GetSettings")
+	defer span.End()
+
+	return s.profileRepo.GetByUserID(ctx, userID)
+}
+
+func (s *ProfileService) UpdateSettings(ctx context.Context, userID int, req *dto.UpdatePublicProfileRequest) (*models.UserProfile, error) {
+	ctx, span := tracing.Start(ctx, "ProfileService.UpdateSettings")
+	defer span.End()
+
+	profile, err := s.profileRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Bio != nil {
+		profile.Bio = req.Bio
+	}
+	if req.SocialLinks != nil {
+		profile.SocialLinks = req.SocialLinks
+	}
+	if req.ShowSocialLinks != nil {
+		profile.ShowSocialLinks = *req.ShowSocialLinks
+	}
+	if req.ShowJoinDate != nil {
+		profile.ShowJoinDate = *req.ShowJoinDate
+	}
+	if req.ShowStats != nil {
+		profile.ShowStats = *req.ShowStats
+	}
+	if req.ShowFollowers != nil {
+		profile.ShowFollowers = *req.ShowFollowers
+	}
+
+	if err := s.profileRepo.Upsert(ctx, profile); err != nil {
+		slog.ErrorContext(ctx, "failed to update profile", "error", err, "user_id", userID)
+		return nil, errors.New("failed to update profile")
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+	"web-be/utils"
+)
+
+type ReadingListService struct {
+	listRepo  *repository.ReadingListRepository
+	storyRepo *repository.StoryRepository
+}
+
+func NewReadingListService(
+	listRepo *repository.ReadingListRepository,
+	storyRepo *repository.StoryRepository,
+) *ReadingListService {
+	return &ReadingListService{
+		listRepo:  listRepo,
+		storyRepo: storyRepo,
+	}
+}
+
+// EnsureDefaultLists creates any built-in lists the user does not have yet
+func (s *ReadingListService) EnsureDefaultLists(ctx context.Context, userID int) error {
+	ctx, span := tracing.Start(ctx, "ReadingListService.EnsureDefaultLists")
+	defer span.End()
+
+	count, err := s.listRepo.CountSystemLists(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if count >= len(models.DefaultReadingLists) {
+		return nil
+	}
+
+	for _, def := range models.DefaultReadingLists {
+		code, err := utils.GenerateShareCode()
+		if err != nil {
+			return err
+		}
+		key := def.Key
+		list := &models.ReadingList{
+			UserID:    userID,
+			Name:      def.Name,
+			ShareCode: code,
+			SystemKey: &key,
+		}
+		if err := s.listRepo.CreateIfMissing(ctx, list); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ReadingListService) GetMyLists(ctx context.Context, userID, storyID int) ([]models.ReadingListSummary, error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.GetMyLists")
+	defer span.End()
+
+	if err := s.EnsureDefaultLists(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "failed to create default reading lists", "error", err, "user_id", userID)
+		return nil, errors.New("failed to get reading lists")
+	}
+
+	lists, err := s.listRepo.GetByUser(ctx, userID, storyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get reading lists", "error", err, "user_id", userID)
+		return nil, errors.New("failed to get reading lists")
+	}
+	return lists, nil
+}
+
+func (s *ReadingListService) Create(ctx context.Context, userID int, req *dto.CreateReadingListRequest) (*dto.ReadingListResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.Create")
+	defer span.End()
+
+	code, err := utils.GenerateShareCode()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate share code", "error", err)
+		return nil, errors.New("failed to create reading list")
+	}
+
+	list := &models.ReadingList{
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		ShareCode:   code,
+		IsPublic:    req.IsPublic,
+	}
+	if err := s.listRepo.Create(ctx, list); err != nil {
+		slog.ErrorContext(ctx, "failed to create reading list", "error", err, "user_id", userID)
+		return nil, errors.New("failed to create reading list")
+	}
+
+	slog.InfoContext(ctx, "reading list created", "list_id", list.ID, "user_id", userID)
+	return s.Get(ctx, userID, code)
+}
+
+// Get returns a list by share code. Private lists are only visible to their
+// owner; viewerID is 0 for anonymous requests.
+func (s *ReadingListService) Get(ctx context.Context, viewerID int, code string) (*dto.ReadingListResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.Get")
+	defer span.End()
+
+	list, err := s.getVisibleList(ctx, viewerID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ReadingListResponse{
+		ReadingListSummary: *list,
+		IsOwner:            list.UserID == viewerID,
+	}
+	if viewerID != 0 && !response.IsOwner {
+		response.IsFollowing, err = s.listRepo.IsFollowing(ctx, viewerID, list.ID)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to check list follow", "error", err, "list_id", list.ID, "user_id", viewerID)
+		}
+	}
+	return response, nil
+}
+
+func (s *ReadingListService) Update(ctx context.Context, userID int, code string, req *dto.UpdateReadingListRequest) (*dto.ReadingListResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.Update")
+	defer span.End()
+
+	list, err := s.getOwnedList(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if list.SystemKey != nil && *req.Name != list.Name {
+			return nil, apperror.Forbidden("builtin_list", "built-in reading lists cannot be renamed")
+		}
+		list.Name = *req.Name
+	}
+	if req.Description != nil {
+		list.Description = req.Description
+	}
+	if req.IsPublic != nil {
+		list.IsPublic = *req.IsPublic
+	}
+
+	if err := s.listRepo.Update(ctx, &list.ReadingList); err != nil {
+		slog.ErrorContext(ctx, "failed to update reading list", "error", err, "list_id", list.ID)
+		return nil, errors.New("failed to update reading list")
+	}
+
+	slog.InfoContext(ctx, "reading list updated", "list_id", list.ID, "user_id", userID)
+	return s.Get(ctx, userID, code)
+}
+
+func (s *ReadingListService) Delete(ctx context.Context, userID int, code string) error {
+	ctx, span := tracing.Start(ctx, "ReadingListService.Delete")
+	defer span.End()
+
+	list, err := s.getOwnedList(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+	if list.SystemKey != nil {
+		return apperror.Forbidden("builtin_list", "built-in reading lists cannot be deleted")
+	}
+
+	if err := s.listRepo.Delete(ctx, list.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to delete reading list", "error", err, "list_id", list.ID)
+		return errors.New("failed to delete reading list")
+	}
+
+	slog.InfoContext(ctx, "reading list deleted", "list_id", list.ID, "user_id", userID)
+	return nil
+}
+
+func (s *ReadingListService) GetItems(ctx context.Context, viewerID int, code string, limit, offset int) ([]models.ReadingListItem, int64, error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.GetItems")
+	defer span.End()
+
+	list, err := s.getVisibleList(ctx, viewerID, code)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, total, err := s.listRepo.GetItems(ctx, list.ID, limit, offset)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get reading list items", "error", err, "list_id", list.ID)
+		return nil, 0, errors.New("failed to get reading list items")
+	}
+	return items, total, nil
+}
+
+func (s *ReadingListService) AddItem(ctx context.Context, userID int, code string, storyID int) error {
+	ctx, span := tracing.Start(ctx, "ReadingListService.AddItem")
+	defer span.End()
+
+	list, err := s.getOwnedList(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	story, err := s.storyRepo.GetByID(ctx, storyID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check story existence", "error", err, "story_id", storyID)
+		return errors.New("failed to add story to reading list")
+	}
+	if story == nil {
+		return apperror.NotFound("story_not_found", "story not found")
+	}
+
+	if err := s.listRepo.AddItem(ctx, list.ID, storyID); err != nil {
+		slog.ErrorContext(ctx, "failed to add reading list item", "error", err, "list_id", list.ID, "story_id", storyID)
+		return errors.New("failed to add story to reading list")
+	}
+
+	slog.InfoContext(ctx, "story added to reading list", "list_id", list.ID, "story_id", storyID)
+	return nil
+}
+
+func (s *ReadingListService) RemoveItem(ctx context.Context, userID int, code string, storyID int) error {
+	ctx, span := tracing.Start(ctx, "ReadingListService.RemoveItem")
+	defer span.End()
+
+	list, err := s.getOwnedList(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	if err := s.listRepo.RemoveItem(ctx, list.ID, storyID); err != nil {
+		slog.ErrorContext(ctx, "failed to remove reading list item", "error", err, "list_id", list.ID, "story_id", storyID)
+		return errors.New("failed to remove story from reading list")
+	}
+
+	slog.InfoContext(ctx, "story removed from reading list", "list_id", list.ID, "story_id", storyID)
+	return nil
+}
+
+// Reorder moves the given stories to the top of the list in that order
+func (s *ReadingListService) Reorder(ctx context.Context, userID int, code string, storyIDs []int) error {
+	ctx, span := tracing.Start(ctx, "ReadingListService.Reorder")
+	defer span.End()
+
+	list, err := s.getOwnedList(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[int]bool, len(storyIDs))
+	for _, id := range storyIDs {
+		if seen[id] {
+			return apperror.InvalidField("duplicate_story_ids", "story_ids", "story_ids must not contain duplicates")
+		}
+		seen[id] = true
+	}
+
+	if err := s.listRepo.ReorderItems(ctx, list.ID, storyIDs); err != nil {
+		slog.ErrorContext(ctx, "failed to reorder reading list", "error", err, "list_id", list.ID)
+		return errors.New("failed to reorder reading list")
+	}
+	return nil
+}
+
+func (s *ReadingListService) Follow(ctx context.Context, userID int, code string) (*dto.ReadingListResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.Follow")
+	defer span.End()
+
+	list, err := s.getVisibleList(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID == userID {
+		return nil, apperror.Validation("cannot_follow_own_list", "you cannot follow your own reading list")
+	}
+
+	if err := s.listRepo.Follow(ctx, userID, list.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to follow reading list", "error", err, "list_id", list.ID, "user_id", userID)
+		return nil, errors.New("failed to follow reading list")
+	}
+
+	slog.InfoContext(ctx, "reading list followed", "list_id", list.ID, "user_id", userID)
+	return s.Get(ctx, userID, code)
+}
+
+func (s *ReadingListService) Unfollow(ctx context.Context, userID int, code string) error {
+	ctx, span := tracing.Start(ctx, "ReadingListService.Unfollow")
+	defer span.End()
+
+	list, err := s.listRepo.GetByShareCode(ctx, code)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get reading list", "error", err, "code", code)
+		return errors.New("failed to unfollow reading list")
+	}
+	if list == nil {
+		return apperror.NotFound("reading_list_not_found", "reading list not found")
+	}
+
+	if err := s.listRepo.Unfollow(ctx, userID, list.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to unfollow reading list", "error", err, "list_id", list.ID, "user_id", userID)
+		return errors.New("failed to unfollow reading list")
+	}
+	return nil
+}
+
+func (s *ReadingListService) GetFollowedLists(ctx context.Context, userID, limit, offset int) ([]models.ReadingListSummary, int64, error) {
+	ctx, span := tracing.Start(ctx, "ReadingListService.GetFollowedLists")
+	defer span.End()
+
+	lists, total, err := s.listRepo.GetFollowedByUser(ctx, userID, limit, offset)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get followed reading lists", "error", err, "user_id", userID)
+		return nil, 0, errors.New("failed to get followed reading lists")
+	}
+	return lists, total, nil
+}
+
+// getVisibleList hides private lists from everyone but the owner
+func (s *ReadingListService) getVisibleList(ctx context.Context, viewerID int, code string) (*models.ReadingListSummary, error) {
+	list, err := s.listRepo.GetByShareCode(ctx, code)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get reading list", "error", err, "code", code)
+		return nil, errors.New("failed to get reading list")
+	}
+	if list == nil || (!list.IsPublic && list.UserID != viewerID) {
+		return nil, apperror.NotFound("reading_list_not_found", "reading list not found")
+	}
+	return list, nil
+}
+
+func (s *ReadingListService) getOwnedList(ctx context.Context, userID int, code string) (*models.ReadingListSummary, error) {
+	list, err := s.getVisibleList(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+	if list.UserID != userID {
+		return nil, apperror.Forbidden("forbidden", "you can only modify your own reading lists")
+	}
+	return list, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+)
+
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 366
+	recapTopLimit    = 5
+)
+
+type ReadingStatsService struct {
+	statsRepo *repository.ReadingStatsRepository
+}
+
+func NewReadingStatsService(statsRepo *repository.ReadingStatsRepository) *ReadingStatsService {
+	return &ReadingStatsService{statsRepo: statsRepo}
+}
+
+// GetStats summarises the user's reading between two dates, the last 30 days by default
+func (s *ReadingStatsService) GetStats(ctx context.Context, userID int, req *dto.DateRangeRequest) (*dto.ReadingStatsResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReadingStatsService.GetStats")
+	defer span.End()
+
+	from, to, err := req.Resolve(defaultStatsDays, maxStatsDays)
+	if err != nil {
+		return nil, err
+	}
+	today := dateOf(time.Now())
+
+	totals, err := s.statsRepo.GetTotals(ctx, userID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get reading totals", "error", err, "user_id", userID)
+		return nil, errors.New("failed to get reading stats")
+	}
+
+	days, err := s.statsRepo.GetDaily(ctx, userID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get daily reading", "error", err, "user_id", userID)
+		return nil, errors.New("failed to get reading stats")
+	}
+
+	categories, err := s.statsRepo.GetTopCategories(ctx, userID, from, to, recapTopLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get favourite categories", "error", err, "user_id", userID)
+		return nil, errors.New("failed to get reading stats")
+	}
+
+	// Streaks look at the whole history, not just the requested range
+	dates, err := s.statsRepo.GetActiveDates(ctx, userID, today)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get reading days", "error", err, "user_id", userID)
+		return nil, errors.New("failed to get reading stats")
+	}
+
+	response := &dto.ReadingStatsResponse{
+		From:                from.Format(dto.DateLayout),
+		To:                  to.Format(dto.DateLayout),
+		Chapters:            totals.Chapters,
+		Words:               totals.Words,
+		Minutes:             readingMinutes(totals.Words),
+		Stories:             totals.Stories,
+		ActiveDays:          totals.ActiveDays,
+		CurrentStreak:       currentStreak(dates, today),
+		LongestStreak:       longestStreak(dates),
+		Daily:               make([]dto.ReadingDayResponse, 0, len(days)),
+		FavouriteCategories: categories,
+	}
+	for _, day := range days {
+		response.Daily = append(response.Daily, toReadingDayResponse(day))
+	}
+
+	return response, nil
+}
+
+// GetRecap builds the yearly summary shown at the end of the year
+func (s *ReadingStatsService) GetRecap(ctx context.Context, userID, year int) (*dto.ReadingRecapResponse, error) {
+	ctx, span := tracing.Start(ctx, "ReadingStatsService.GetRecap")
+	defer span.End()
+
+	now := time.Now()
+	if year < 2000 || year > now.Year() {
+		return nil, apperror.InvalidField("invalid_year", "year", "invalid year")
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	totals, err := s.statsRepo.GetTotals(ctx, userID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get reading totals", "error", err, "user_id", userID, "year", year)
+		return nil, errors.New("failed to get reading recap")
+	}
+
+	days, err := s.statsRepo.GetDaily(ctx, userID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get daily reading", "error", err, "user_id", userID, "year", year)
+		return nil, errors.New("failed to get reading recap")
+	}
+
+	categories, err := s.statsRepo.GetTopCategories(ctx, userID, from, to, recapTopLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get top categories", "error", err, "user_id", userID, "year", year)
+		return nil, errors.New("failed to get reading recap")
+	}
+
+	stories, err := s.statsRepo.GetTopStories(ctx, userID, from, to, recapTopLimit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get top stories", "error", err, "user_id", userID, "year", year)
+		return nil, errors.New("failed to get reading recap")
+	}
+
+	started, err := s.statsRepo.CountStoriesStarted(ctx, userID, from, to)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to count stories started", "error", err, "user_id", userID, "year", year)
+		return nil, errors.New("failed to get reading recap")
+	}
+
+	response := &dto.ReadingRecapResponse{
+		Year:           year,
+		Chapters:       totals.Chapters,
+		Words:          totals.Words,
+		Minutes:        readingMinutes(totals.Words),
+		Stories:        totals.Stories,
+		StoriesStarted: started,
+		ActiveDays:     totals.ActiveDays,
+		Months:         make([]dto.ReadingMonthResponse, 12),
+		TopCategories:  categories,
+		TopStories:     stories,
+	}
+
+	dates := make([]time.Time, 0, len(days))
+	var busiest *models.ReadingDay
+	for i, day := range days {
+		dates = append(dates, day.Date)
+
+		month := &response.Months[day.Date.Month()-1]
+		month.Chapters += day.Chapters
+		month.Words += day.Words
+
+		if busiest == nil || day.Chapters > busiest.Chapters {
+			busiest = &days[i]
+		}
+	}
+	for i := range response.Months {
+		response.Months[i].Month = i + 1
+	}
+	if busiest != nil {
+		day := toReadingDayResponse(*busiest)
+		response.BusiestDay = &day
+	}
+	response.LongestStreak = longestStreak(dates)
+
+	return response, nil
+}
+
+func toReadingDayResponse(day models.ReadingDay) dto.ReadingDayResponse {
+	return dto.ReadingDayResponse{
+		Date:     day.Date.Format(dto.DateLayout),
+		Chapters: day.Chapters,
+		Words:    day.Words,
+		Minutes:  readingMinutes(day.Words),
+	}
+}
+
+func readingMinutes(words int64) int64 {
+	return (words + models.ReadingWordsPerMinute - 1) / models.ReadingWordsPerMinute
+}
+
+// dateOf drops the time of day so dates compare like the DATE column
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// currentStreak counts consecutive reading days ending today, or yesterday
+// if the user has not read yet today. dates must be newest first.
+func currentStreak(dates []time.Time, today time.Time) int {
+	if len(dates) == 0 {
+		return 0
+	}
+	expected := today
+	if dateOf(dates[0]).Before(today) {
+		expected = today.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for _, d := range dates {
+		if !dateOf(d).Equal(expected) {
+			break
+		}
+		streak++
+		expected = expected.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// longestStreak returns the longest run of consecutive days in sorted, distinct dates
+func longestStreak(dates []time.Time) int {
+	longest, run := 0, 0
+	for i, d := range dates {
+		if i > 0 {
+			gap := dateOf(d).Sub(dateOf(dates[i-1]))
+			if gap < 0 {
+				gap = -gap
+			}
+			if gap == 24*time.Hour {
+				run++
+			} else {
+				run = 1
+			}
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+	}
+	return longest
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"web-be/apperror"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+)
+
+type SessionService struct {
+	sessionRepo *repository.SessionRepository
+	userRepo    *repository.UserRepository
+	auditRepo   *repository.AdminAuditRepository
+}
+
+func NewSessionService(
+	sessionRepo *repository.SessionRepository,
+	userRepo *repository.UserRepository,
+	auditRepo *repository.AdminAuditRepository,
+) *SessionService {
+	return &SessionService{sessionRepo: sessionRepo, userRepo: userRepo, auditRepo: auditRepo}
+}
+
+// Validate checks that a token's session is still active and records activity.
+// The session returned also says whether the user must reset their password.
+func (s *SessionService) Validate(ctx context.Context, sessionID, userID int) (*models.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.Validate")
+	defer span.End()
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userID || !session.IsActive(time.Now()) {
+		return nil, apperror.Unauthorized("session_expired", "session revoked or expired")
+	}
+
+	if err := s.sessionRepo.TouchLastSeen(ctx, sessionID); err != nil {
+		slog.WarnContext(ctx, "failed to update session last seen", "error", err, "session_id", sessionID)
+	}
+	return session, nil
+}
+
+// ValidateUnbound checks the account behind a token issued before sessions
+// existed. Such a token cannot be revoked, so bans and suspensions are looked
+// up on every request instead.
+func (s *SessionService) ValidateUnbound(ctx context.Context, userID int) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.ValidateUnbound")
+	defer span.End()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || !user.IsActive || user.IsSuspended(time.Now()) {
+		return nil, apperror.Unauthorized("session_expired", "session revoked or expired")
+	}
+	return user, nil
+}
+
+// RecordImpersonatedRequest writes a request made under an impersonation
+// session to the admin audit log, so every page support looked at is on record
+func (s *SessionService) RecordImpersonatedRequest(ctx context.Context, impersonatorID, userID, sessionID int, method, path, ipAddress string) {
+	details, _ := json.Marshal(map[string]interface{}{
+		"session_id": sessionID,
+		"method":     method,
+		"path":       path,
+	})
+	entry := &models.AdminAuditLog{
+		ActorID:      &impersonatorID,
+		Action:       models.AuditActionImpersonatedRequest,
+		TargetUserID: &userID,
+		Details:      details,
+	}
+	if ipAddress != "" {
+		entry.IPAddress = &ipAddress
+	}
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "failed to write admin audit log", "error", err, "action", entry.Action, "actor_id", impersonatorID, "target_user_id", userID)
+	}
+}
+
+func (s *SessionService) List(ctx context.Context, userID, currentSessionID int) ([]dto.SessionResponse, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.List")
+	defer span.End()
+
+	sessions, err := s.sessionRepo.GetActiveByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, dto.SessionResponse{
+			ID:             session.ID,
+			UserAgent:      session.UserAgent,
+			IPAddress:      session.IPAddress,
+			CreatedAt:      session.CreatedAt,
+			LastSeenAt:     session.LastSeenAt,
+			ExpiresAt:      session.ExpiresAt,
+			Current:        session.ID == currentSessionID,
+			ImpersonatorID: session.ImpersonatorID,
+		})
+	}
+	return responses, nil
+}
+
+func (s *SessionService) Revoke(ctx context.Context, userID, sessionID int) error {
+	ctx, span := tracing.Start(ctx, "SessionService.Revoke")
+	defer span.End()
+
+	revoked, err := s.sessionRepo.Revoke(ctx, sessionID, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to revoke session", "error", err, "session_id", sessionID, "user_id", userID)
+		return errors.New("failed to revoke session")
+	}
+	if !revoked {
+		return apperror.NotFound("session_not_found", "session not found")
+	}
+
+	slog.InfoContext(ctx, "session revoked", "session_id", sessionID, "user_id", userID)
+	return nil
+}
+
+// RevokeOthers signs the user out everywhere except the current session
+func (s *SessionService) RevokeOthers(ctx context.Context, userID, currentSessionID int) (int64, error) {
+	ctx, span := tracing.Start(ctx, "SessionService.RevokeOthers")
+	defer span.End()
+
+	count, err := s.sessionRepo.RevokeAllExcept(ctx, userID, currentSessionID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to revoke sessions", "error", err, "user_id", userID)
+		return 0, errors.New("failed to revoke sessions")
+	}
+
+	slog.InfoContext(ctx, "other sessions revoked", "user_id", userID, "count", count)
+	return count, nil
+}
@@ -3,40 +3,49 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
 
+	"web-be/apperror"
+	"web-be/cache"
 	"web-be/dto"
+	"web-be/metrics"
 	"web-be/models"
 	"web-be/repository"
+	"web-be/tracing"
 	"web-be/utils"
 )
 
 type StoryService struct {
-	storyRepo    *repository.StoryRepository
-	categoryRepo *repository.CategoryRepository
-	historyRepo  *repository.ReadingHistoryRepository
+	storyRepo   *repository.StoryRepository
+	historyRepo *repository.ReadingHistoryRepository
+	tagRepo     *repository.TagRepository
+	cache       cache.Cache
 }
 
 func NewStoryService(
 	storyRepo *repository.StoryRepository,
-	categoryRepo *repository.CategoryRepository,
 	historyRepo *repository.ReadingHistoryRepository,
+	tagRepo *repository.TagRepository,
+	cache cache.Cache,
 ) *StoryService {
 	return &StoryService{
-		storyRepo:    storyRepo,
-		categoryRepo: categoryRepo,
-		historyRepo:  historyRepo,
+		storyRepo:   storyRepo,
+		historyRepo: historyRepo,
+		tagRepo:     tagRepo,
+		cache:       cache,
 	}
 }
 
 func (s *StoryService) Create(ctx context.Context, authorID int, req *dto.CreateStoryRequest) (*models.Story, error) {
-	slug := utils.GenerateSlug(req.Title)
+	ctx, span := tracing.Start(ctx, "StoryService.Create")
+	defer span.End()
 
-	// Check if slug exists
-	existing, _ := s.storyRepo.GetBySlug(ctx, slug)
-	if existing != nil {
-		slog.Warn("story creation failed: duplicate slug", "slug", slug)
-		return nil, errors.New("a story with this title already exists")
+	slug, err := s.uniqueSlug(ctx, req.Title, 0)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate story slug", "error", err, "title", req.Title)
+		return nil, errors.New("failed to create story")
 	}
 
 	status := "ongoing"
@@ -54,9 +63,9 @@ func (s *StoryService) Create(ctx context.Context, authorID int, req *dto.Create
 		Status:        status,
 	}
 
-	err := s.storyRepo.Create(ctx, story)
+	err = s.storyRepo.Create(ctx, story)
 	if err != nil {
-		slog.Error("failed to create story", "error", err, "title", req.Title)
+		slog.ErrorContext(ctx, "failed to create story", "error", err, "title", req.Title)
 		return nil, errors.New("failed to create story")
 	}
 
@@ -68,104 +77,145 @@ func (s *StoryService) Create(ctx context.Context, authorID int, req *dto.Create
 		}
 	}
 
-	// Load categories
-	categories, _ := s.storyRepo.GetCategories(ctx, story.ID)
-	story.Categories = categories
+	if len(req.Tags) > 0 {
+		if err := s.setTags(ctx, story.ID, req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	s.loadRelations(ctx, story)
 
-	slog.Info("story created", "id", story.ID, "slug", story.Slug, "author_id", authorID)
+	slog.InfoContext(ctx, "story created", "id", story.ID, "slug", story.Slug, "author_id", authorID)
 	return story, nil
 }
 
+// GetBySlug returns a story with its categories and tags. The story is served
+// from the cache, so its view count can lag by up to storyCacheTTL; every
+// call still counts a view.
 func (s *StoryService) GetBySlug(ctx context.Context, slug string) (*models.Story, error) {
-	story, err := s.storyRepo.GetBySlug(ctx, slug)
+	ctx, span := tracing.Start(ctx, "StoryService.GetBySlug")
+	defer span.End()
+
+	story, err := cache.Fetch(ctx, s.cache, storyCacheKey(slug), storyCacheTTL, func(ctx context.Context) (*models.Story, error) {
+		story, err := s.storyRepo.GetBySlug(ctx, slug)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to get story by slug", "error", err, "slug", slug)
+			return nil, err
+		}
+		if story == nil {
+			slog.DebugContext(ctx, "story not found", "slug", slug)
+			return nil, apperror.NotFound("story_not_found", "story not found")
+		}
+
+		s.loadRelations(ctx, story)
+		return story, nil
+	})
 	if err != nil {
-		slog.Error("failed to get story by slug", "error", err, "slug", slug)
 		return nil, err
 	}
-	if story == nil {
-		slog.Debug("story not found", "slug", slug)
-		return nil, errors.New("story not found")
-	}
 
 	// Increment views
 	_ = s.storyRepo.IncrementViews(ctx, story.ID)
-	slog.Debug("story views incremented", "story_id", story.ID, "slug", slug)
-
-	// Load categories
-	categories, _ := s.storyRepo.GetCategories(ctx, story.ID)
-	story.Categories = categories
+	metrics.ViewsRecorded.WithLabelValues("story").Inc()
+	slog.DebugContext(ctx, "story views incremented", "story_id", story.ID, "slug", slug)
 
 	return story, nil
 }
 
-func (s *StoryService) GetAll(ctx context.Context, limit, offset int) ([]models.Story, int64, error) {
-	stories, total, err := s.storyRepo.GetAll(ctx, limit, offset)
+// GetAll lists stories, optionally restricted to those carrying every tag in tagSlugs
+func (s *StoryService) GetAll(ctx context.Context, tagSlugs []string, limit, offset int) ([]models.Story, int64, error) {
+	ctx, span := tracing.Start(ctx, "StoryService.GetAll")
+	defer span.End()
+
+	tagIDs, ok, err := resolveTagSlugs(ctx, s.tagRepo, tagSlugs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return []models.Story{}, 0, nil
+	}
+
+	stories, total, err := s.storyRepo.GetAll(ctx, tagIDs, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	// Load categories for each story
+	// Load categories and tags for each story
 	for i := range stories {
-		categories, _ := s.storyRepo.GetCategories(ctx, stories[i].ID)
-		stories[i].Categories = categories
+		s.loadRelations(ctx, &stories[i])
 	}
 
 	return stories, total, nil
 }
 
-func (s *StoryService) Search(ctx context.Context, keyword string, limit, offset int) ([]models.Story, int64, error) {
-	stories, total, err := s.storyRepo.Search(ctx, keyword, limit, offset)
+func (s *StoryService) Search(ctx context.Context, keyword string, tagSlugs []string, limit, offset int) ([]models.Story, int64, error) {
+	ctx, span := tracing.Start(ctx, "StoryService.Search")
+	defer span.End()
+
+	tagIDs, ok, err := resolveTagSlugs(ctx, s.tagRepo, tagSlugs)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return []models.Story{}, 0, nil
+	}
+
+	stories, total, err := s.storyRepo.Search(ctx, keyword, tagIDs, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	for i := range stories {
-		categories, _ := s.storyRepo.GetCategories(ctx, stories[i].ID)
-		stories[i].Categories = categories
+		s.loadRelations(ctx, &stories[i])
 	}
 
 	return stories, total, nil
 }
 
-func (s *StoryService) GetByCategory(ctx context.Context, categorySlug string, limit, offset int) ([]models.Story, int64, error) {
-	category, err := s.categoryRepo.GetBySlug(ctx, categorySlug)
-	if err != nil {
-		return nil, 0, err
-	}
-	if category == nil {
-		return nil, 0, errors.New("category not found")
-	}
+// GetByCategory lists published stories in a category and its subcategories
+func (s *StoryService) GetByCategory(ctx context.Context, categoryID, limit, offset int) ([]models.Story, int64, error) {
+	ctx, span := tracing.Start(ctx, "StoryService.GetByCategory")
+	defer span.End()
 
-	stories, total, err := s.storyRepo.GetByCategory(ctx, category.ID, limit, offset)
+	stories, total, err := s.storyRepo.GetByCategory(ctx, categoryID, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	for i := range stories {
-		categories, _ := s.storyRepo.GetCategories(ctx, stories[i].ID)
-		stories[i].Categories = categories
+		s.loadRelations(ctx, &stories[i])
 	}
 
 	return stories, total, nil
 }
 
 func (s *StoryService) Update(ctx context.Context, slug string, userID int, userRole string, req *dto.UpdateStoryRequest) (*models.Story, error) {
+	ctx, span := tracing.Start(ctx, "StoryService.Update")
+	defer span.End()
+
 	story, err := s.storyRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return nil, err
 	}
 	if story == nil {
-		return nil, errors.New("story not found")
+		return nil, apperror.NotFound("story_not_found", "story not found")
 	}
 
 	// Check permission
 	if userRole != "admin" && (story.AuthorID == nil || *story.AuthorID != userID) {
-		return nil, errors.New("you don't have permission to edit this story")
+		return nil, apperror.Forbidden("forbidden", "you don't have permission to edit this story")
 	}
 
+	oldSlug := story.Slug
 	if req.Title != nil {
 		story.Title = *req.Title
-		story.Slug = utils.GenerateSlug(*req.Title)
+		if !req.KeepSlug {
+			story.Slug, err = s.uniqueSlug(ctx, *req.Title, story.ID)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to generate story slug", "error", err, "story_id", story.ID)
+				return nil, errors.New("failed to update story")
+			}
+		}
 	}
 	if req.Description != nil {
 		story.Description = req.Description
@@ -180,10 +230,12 @@ func (s *StoryService) Update(ctx context.Context, slug string, userID int, user
 		story.Status = *req.Status
 	}
 
-	err = s.storyRepo.Update(ctx, story)
+	err = s.storyRepo.Update(ctx, story, oldSlug)
 	if err != nil {
 		return nil, errors.New("failed to update story")
 	}
+	// Categories and tags are written next; drop the cached copy once they are
+	defer cache.Invalidate(ctx, s.cache, storyCacheKey(oldSlug), storyCacheKey(story.Slug), categoryTreeCacheKey)
 
 	if len(req.CategoryIDs) > 0 {
 		err = s.storyRepo.SetCategories(ctx, story.ID, req.CategoryIDs)
@@ -192,82 +244,156 @@ func (s *StoryService) Update(ctx context.Context, slug string, userID int, user
 		}
 	}
 
+	// A nil list leaves tags untouched; an empty list clears them
+	if req.Tags != nil {
+		if err := s.setTags(ctx, story.ID, req.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	s.loadRelations(ctx, story)
+
+	return story, nil
+}
+
+func (s *StoryService) setTags(ctx context.Context, storyID int, names []string) error {
+	tagIDs, err := resolveTagNames(ctx, s.tagRepo, names)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to resolve tags", "error", err, "story_id", storyID)
+		return errors.New("failed to set tags")
+	}
+	if len(tagIDs) > models.MaxTagsPerStory {
+		return apperror.InvalidField("too_many_tags", "tags", "too many tags")
+	}
+
+	if err := s.tagRepo.SetStoryTags(ctx, storyID, tagIDs); err != nil {
+		slog.ErrorContext(ctx, "failed to set story tags", "error", err, "story_id", storyID)
+		return errors.New("failed to set tags")
+	}
+	return nil
+}
+
+// loadRelations fills in the categories and tags of a story
+func (s *StoryService) loadRelations(ctx context.Context, story *models.Story) {
 	categories, _ := s.storyRepo.GetCategories(ctx, story.ID)
 	story.Categories = categories
 
-	return story, nil
+	tags, _ := s.tagRepo.GetByStory(ctx, story.ID)
+	story.Tags = tags
 }
 
 func (s *StoryService) Delete(ctx context.Context, slug string, userID int, userRole string) error {
+	ctx, span := tracing.Start(ctx, "StoryService.Delete")
+	defer span.End()
+
 	story, err := s.storyRepo.GetBySlug(ctx, slug)
 	if err != nil {
 		return err
 	}
 	if story == nil {
-		return errors.New("story not found")
+		return apperror.NotFound("story_not_found", "story not found")
 	}
 
 	if userRole != "admin" && (story.AuthorID == nil || *story.AuthorID != userID) {
-		slog.Warn("unauthorized story deletion attempt", "user_id", userID, "story_id", story.ID)
-		return errors.New("you don't have permission to delete this story")
+		slog.WarnContext(ctx, "unauthorized story deletion attempt", "user_id", userID, "story_id", story.ID)
+		return apperror.Forbidden("forbidden", "you don't have permission to delete this story")
 	}
 
 	err = s.storyRepo.Delete(ctx, story.ID)
 	if err == nil {
-		slog.Info("story deleted", "story_id", story.ID, "slug", slug, "by_user", userID)
+		cache.Invalidate(ctx, s.cache, storyCacheKey(story.Slug), categoryTreeCacheKey)
+		slog.InfoContext(ctx, "story moved to trash", "story_id", story.ID, "slug", slug, "by_user", userID)
 	}
 	return err
 }
 
+// SubmitForReview puts an author's unpublished story in the queue of stories
+// waiting for an admin to publish them
+func (s *StoryService) SubmitForReview(ctx context.Context, slug string, userID int) (*models.Story, error) {
+	ctx, span := tracing.Start(ctx, "StoryService.SubmitForReview")
+	defer span.End()
+
+	story, err := s.storyRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if story == nil {
+		return nil, apperror.NotFound("story_not_found", "story not found")
+	}
+	if story.AuthorID == nil || *story.AuthorID != userID {
+		return nil, apperror.Forbidden("forbidden", "you don't have permission to submit this story")
+	}
+	if story.IsPublished {
+		return nil, apperror.Conflict("story_already_published", "story is already published")
+	}
+
+	submittedAt, err := s.storyRepo.SubmitForReview(ctx, story.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to submit story for review", "error", err, "story_id", story.ID)
+		return nil, errors.New("failed to submit story")
+	}
+	story.SubmittedAt = &submittedAt
+
+	cache.Invalidate(ctx, s.cache, storyCacheKey(story.Slug))
+	slog.InfoContext(ctx, "story submitted for review", "story_id", story.ID, "by_user", userID)
+	return story, nil
+}
+
 func (s *StoryService) Publish(ctx context.Context, id int, publish bool) error {
+	ctx, span := tracing.Start(ctx, "StoryService.Publish")
+	defer span.End()
+
 	story, err := s.storyRepo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	if story == nil {
-		return errors.New("story not found")
+		return apperror.NotFound("story_not_found", "story not found")
 	}
 
-	return s.storyRepo.Publish(ctx, id, publish)
+	if err := s.storyRepo.Publish(ctx, id, publish); err != nil {
+		return err
+	}
+	cache.Invalidate(ctx, s.cache, storyCacheKey(story.Slug), categoryTreeCacheKey)
+	return nil
 }
 
 func (s *StoryService) GetByAuthor(ctx context.Context, authorID, limit, offset int) ([]models.Story, int64, error) {
-	return s.storyRepo.GetByAuthor(ctx, authorID, limit, offset)
-}
+	ctx, span := tracing.Start(ctx, "StoryService.GetByAuthor")
+	defer span.End()
 
-// Category methods
-func (s *StoryService) GetAllCategories(ctx context.Context) ([]models.Category, error) {
-	return s.categoryRepo.GetAll(ctx)
+	return s.storyRepo.GetByAuthor(ctx, authorID, limit, offset)
 }
 
-func (s *StoryService) CreateCategory(ctx context.Context, req *dto.CreateCategoryRequest) (*models.Category, error) {
-	slug := utils.GenerateSlug(req.Name)
-
-	existing, _ := s.categoryRepo.GetBySlug(ctx, slug)
-	if existing != nil {
-		return nil, errors.New("category already exists")
-	}
-
-	category := &models.Category{
-		Name:        req.Name,
-		Slug:        slug,
-		Description: req.Description,
-	}
+// ResolveOldSlug returns the current slug of a story that used to be at slug,
+// or "" when slug was never renamed away from
+func (s *StoryService) ResolveOldSlug(ctx context.Context, slug string) (string, error) {
+	ctx, span := tracing.Start(ctx, "StoryService.ResolveOldSlug")
+	defer span.End()
 
-	err := s.categoryRepo.Create(ctx, category)
-	if err != nil {
-		return nil, errors.New("failed to create category")
-	}
+	return s.storyRepo.GetCurrentSlug(ctx, slug)
+}
 
-	return category, nil
+// uniqueSlug derives a slug from title that no other story uses or used,
+// suffixing -2, -3, ... on collision
+func (s *StoryService) uniqueSlug(ctx context.Context, title string, storyID int) (string, error) {
+	return uniqueSlug(utils.GenerateSlug(title), "story", func(slug string) (bool, error) {
+		return s.storyRepo.SlugTaken(ctx, slug, storyID)
+	})
 }
 
 // Reading history methods
 func (s *StoryService) GetReadingHistory(ctx context.Context, userID, limit, offset int) ([]models.ReadingHistoryWithDetails, int64, error) {
+	ctx, span := tracing.Start(ctx, "StoryService.GetReadingHistory")
+	defer span.End()
+
 	return s.historyRepo.GetByUser(ctx, userID, limit, offset)
 }
 
 func (s *StoryService) UpdateReadingHistory(ctx context.Context, userID, storyID int, chapterID *int) error {
+	ctx, span := tracing.Start(ctx, "StoryService.UpdateReadingHistory")
+	defer span.End()
+
 	history := &models.ReadingHistory{
 		UserID:        userID,
 		StoryID:       storyID,
@@ -277,5 +403,39 @@ func (s *StoryService) UpdateReadingHistory(ctx context.Context, userID, storyID
 }
 
 func (s *StoryService) DeleteReadingHistory(ctx context.Context, userID, storyID int) error {
+	ctx, span := tracing.Start(ctx, "StoryService.DeleteReadingHistory")
+	defer span.End()
+
 	return s.historyRepo.Delete(ctx, userID, storyID)
 }
+
+// maxSlugSuffix bounds the collision search before falling back to a random suffix
+const maxSlugSuffix = 100
+
+// uniqueSlug returns base, or base with the first numeric suffix for which
+// taken reports false. fallback replaces a base that is empty.
+func uniqueSlug(base, fallback string, taken func(slug string) (bool, error)) (string, error) {
+	if base == "" {
+		base = fallback
+	}
+
+	for i := 1; i <= maxSlugSuffix; i++ {
+		slug := base
+		if i > 1 {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		isTaken, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+		if !isTaken {
+			return slug, nil
+		}
+	}
+
+	suffix, err := utils.GenerateShareCode()
+	if err != nil {
+		return "", err
+	}
+	return base + "-" + strings.ToLower(suffix), nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"web-be/apperror"
+	"web-be/cache"
+	"web-be/dto"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+	"web-be/utils"
+)
+
+const defaultTagSearchLimit = 10
+
+type TagService struct {
+	tagRepo *repository.TagRepository
+	cache   cache.Cache
+}
+
+func NewTagService(tagRepo *repository.TagRepository, cache cache.Cache) *TagService {
+	return &TagService{tagRepo: tagRepo, cache: cache}
+}
+
+// Search powers tag autocomplete; synonyms match but their canonical tag is returned
+func (s *TagService) Search(ctx context.Context, req *dto.TagSearchRequest) ([]models.Tag, error) {
+	ctx, span := tracing.Start(ctx, "TagService.Search")
+	defer span.End()
+
+	limit := req.Limit
+	if limit < 1 {
+		limit = defaultTagSearchLimit
+	}
+
+	tags, err := s.tagRepo.Search(ctx, utils.GenerateSlug(req.Query), limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to search tags", "error", err, "query", req.Query)
+		return nil, errors.New("failed to search tags")
+	}
+	return tags, nil
+}
+
+// GetBySlug returns a tag page header; a synonym slug resolves to its canonical tag
+func (s *TagService) GetBySlug(ctx context.Context, slug string) (*dto.TagResponse, error) {
+	ctx, span := tracing.Start(ctx, "TagService.GetBySlug")
+	defer span.End()
+
+	tag, err := s.getCanonical(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	synonyms, err := s.tagRepo.GetSynonyms(ctx, tag.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get tag synonyms", "error", err, "tag_id", tag.ID)
+		return nil, errors.New("failed to get tag")
+	}
+
+	return &dto.TagResponse{Tag: *tag, Synonyms: synonyms}, nil
+}
+
+// AddSynonym makes name resolve to the tag identified by slug
+func (s *TagService) AddSynonym(ctx context.Context, slug string, req *dto.AddTagSynonymRequest) (*dto.TagResponse, error) {
+	ctx, span := tracing.Start(ctx, "TagService.AddSynonym")
+	defer span.End()
+
+	tag, err := s.getCanonical(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	name := utils.NormalizeTagName(req.Name)
+	synonymSlug := utils.GenerateSlug(name)
+	if synonymSlug == "" {
+		return nil, apperror.InvalidField("invalid_tag_name", "name", "invalid tag name")
+	}
+
+	existing, err := s.tagRepo.GetBySlug(ctx, synonymSlug)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, apperror.Conflict("tag_exists", "a tag with this name already exists; merge it instead")
+	}
+
+	synonym := &models.Tag{Name: name, Slug: synonymSlug, CanonicalID: &tag.ID}
+	if err := s.tagRepo.CreateSynonym(ctx, synonym); err != nil {
+		slog.ErrorContext(ctx, "failed to create tag synonym", "error", err, "tag_id", tag.ID, "name", name)
+		return nil, errors.New("failed to add synonym")
+	}
+
+	s.invalidateStories(ctx, tag.ID)
+	slog.InfoContext(ctx, "tag synonym added", "tag_id", tag.ID, "synonym", synonymSlug)
+	return s.GetBySlug(ctx, tag.Slug)
+}
+
+// Merge folds the tag identified by slug into another tag; its stories move
+// over and its name becomes a synonym
+func (s *TagService) Merge(ctx context.Context, slug string, req *dto.MergeTagRequest) (*dto.TagResponse, error) {
+	ctx, span := tracing.Start(ctx, "TagService.Merge")
+	defer span.End()
+
+	source, err := s.getCanonical(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.getCanonical(ctx, req.Into)
+	if err != nil {
+		return nil, err
+	}
+	if source.ID == target.ID {
+		return nil, apperror.InvalidField("merge_into_self", "into", "cannot merge a tag into itself")
+	}
+
+	if err := s.tagRepo.Merge(ctx, source.ID, target.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to merge tags", "error", err, "source_id", source.ID, "target_id", target.ID)
+		return nil, errors.New("failed to merge tags")
+	}
+
+	// The source's stories now carry the target, so this covers both
+	s.invalidateStories(ctx, target.ID)
+	slog.InfoContext(ctx, "tags merged", "source", source.Slug, "target", target.Slug)
+	return s.GetBySlug(ctx, target.Slug)
+}
+
+// invalidateStories drops the cached details of every story carrying the tag
+func (s *TagService) invalidateStories(ctx context.Context, tagID int) {
+	slugs, err := s.tagRepo.GetStorySlugs(ctx, tagID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list tagged stories for cache invalidation", "error", err, "tag_id", tagID)
+		return
+	}
+	invalidateStories(ctx, s.cache, slugs)
+}
+
+func (s *TagService) getCanonical(ctx context.Context, slug string) (*models.Tag, error) {
+	tag, err := s.tagRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get tag", "error", err, "slug", slug)
+		return nil, errors.New("failed to get tag")
+	}
+	if tag == nil {
+		return nil, apperror.NotFound("tag_not_found", "tag not found")
+	}
+	return canonicalTag(ctx, s.tagRepo, tag)
+}
+
+// canonicalTag follows a synonym to the tag it stands for
+func canonicalTag(ctx context.Context, tagRepo *repository.TagRepository, tag *models.Tag) (*models.Tag, error) {
+	if !tag.IsSynonym() {
+		return tag, nil
+	}
+	canonical, err := tagRepo.GetByID(ctx, *tag.CanonicalID)
+	if err != nil {
+		return nil, err
+	}
+	if canonical == nil {
+		return nil, apperror.NotFound("tag_not_found", "tag not found")
+	}
+	return canonical, nil
+}
+
+// resolveTagNames turns author-entered names into canonical tag IDs,
+// creating tags that do not exist yet
+func resolveTagNames(ctx context.Context, tagRepo *repository.TagRepository, names []string) ([]int, error) {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, raw := range names {
+		name := utils.NormalizeTagName(raw)
+		slug := utils.GenerateSlug(name)
+		if slug == "" {
+			continue
+		}
+
+		tag, err := tagRepo.GetOrCreate(ctx, name, slug)
+		if err != nil {
+			return nil, err
+		}
+		tag, err = canonicalTag(ctx, tagRepo, tag)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			ids = append(ids, tag.ID)
+		}
+	}
+	return ids, nil
+}
+
+// resolveTagSlugs maps a tag filter to distinct canonical IDs, so a tag named
+// twice or by a synonym is only required once. ok is false when any slug is
+// unknown, meaning nothing can match the filter.
+func resolveTagSlugs(ctx context.Context, tagRepo *repository.TagRepository, slugs []string) (ids []int, ok bool, err error) {
+	seen := make(map[int]bool)
+	for _, slug := range slugs {
+		tag, err := tagRepo.GetBySlug(ctx, slug)
+		if err != nil {
+			return nil, false, err
+		}
+		if tag == nil {
+			return nil, false, nil
+		}
+		tag, err = canonicalTag(ctx, tagRepo, tag)
+		if err != nil {
+			return nil, false, err
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			ids = append(ids, tag.ID)
+		}
+	}
+	return ids, true, nil
+}
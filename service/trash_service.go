@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"web-be/apperror"
+	"web-be/cache"
+	"web-be/models"
+	"web-be/repository"
+	"web-be/tracing"
+)
+
+type TrashService struct {
+	trashRepo   *repository.TrashRepository
+	storyRepo   *repository.StoryRepository
+	chapterRepo *repository.ChapterRepository
+	cache       cache.Cache
+	retention   time.Duration
+}
+
+func NewTrashService(
+	trashRepo *repository.TrashRepository,
+	storyRepo *repository.StoryRepository,
+	chapterRepo *repository.ChapterRepository,
+	cache cache.Cache,
+	retentionDays int,
+) *TrashService {
+	return &TrashService{
+		trashRepo:   trashRepo,
+		storyRepo:   storyRepo,
+		chapterRepo: chapterRepo,
+		cache:       cache,
+		retention:   time.Duration(retentionDays) * 24 * time.Hour,
+	}
+}
+
+// List returns trashed items with the time each will be purged. A nil
+// authorID lists every author's trash (admin view).
+func (s *TrashService) List(ctx context.Context, authorID *int, limit, offset int) ([]models.TrashItem, int64, error) {
+	ctx, span := tracing.Start(ctx, "TrashService.List")
+	defer span.End()
+
+	items, total, err := s.trashRepo.List(ctx, authorID, limit, offset)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list trash", "error", err)
+		return nil, 0, errors.New("failed to get trash")
+	}
+
+	for i := range items {
+		items[i].PurgeAt = items[i].DeletedAt.Add(s.retention)
+	}
+	return items, total, nil
+}
+
+func (s *TrashService) RestoreStory(ctx context.Context, id int, userID int, userRole string) error {
+	ctx, span := tracing.Start(ctx, "TrashService.RestoreStory")
+	defer span.End()
+
+	story, err := s.trashRepo.GetStory(ctx, id)
+	if err != nil {
+		return err
+	}
+	if story == nil || story.DeletedAt == nil {
+		return apperror.NotFound("story_not_in_trash", "story not found in trash")
+	}
+
+	if userRole != "admin" && (story.AuthorID == nil || *story.AuthorID != userID) {
+		return apperror.Forbidden("forbidden", "you don't have permission to restore this story")
+	}
+	if s.expired(*story.DeletedAt) {
+		return apperror.Conflict("retention_expired", "the retention window for this story has passed")
+	}
+
+	if err := s.trashRepo.RestoreStory(ctx, id); err != nil {
+		slog.ErrorContext(ctx, "failed to restore story", "error", err, "story_id", id)
+		return errors.New("failed to restore story")
+	}
+
+	cache.Invalidate(ctx, s.cache, categoryTreeCacheKey)
+	slog.InfoContext(ctx, "story restored", "story_id", id, "by_user", userID)
+	return nil
+}
+
+func (s *TrashService) RestoreChapter(ctx context.Context, id int, userID int, userRole string) error {
+	ctx, span := tracing.Start(ctx, "TrashService.RestoreChapter")
+	defer span.End()
+
+	chapter, err := s.trashRepo.GetDeletedChapter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if chapter == nil {
+		return apperror.NotFound("chapter_not_in_trash", "chapter not found in trash")
+	}
+
+	story, err := s.trashRepo.GetStory(ctx, chapter.StoryID)
+	if err != nil {
+		return err
+	}
+	if story == nil {
+		return apperror.NotFound("story_not_found", "story not found")
+	}
+
+	if userRole != "admin" && (story.AuthorID == nil || *story.AuthorID != userID) {
+		return apperror.Forbidden("forbidden", "you don't have permission to restore this chapter")
+	}
+	if story.DeletedAt != nil {
+		return apperror.Conflict("story_in_trash", "the story is in the trash; restore the story first")
+	}
+	if s.expired(*chapter.DeletedAt) {
+		return apperror.Conflict("retention_expired", "the retention window for this chapter has passed")
+	}
+
+	if err := s.trashRepo.RestoreChapter(ctx, id); err != nil {
+		slog.ErrorContext(ctx, "failed to restore chapter", "error", err, "chapter_id", id)
+		return errors.New("failed to restore chapter")
+	}
+	_ = s.storyRepo.UpdateChapterCount(ctx, story.ID)
+	invalidateChapter(ctx, s.cache, s.chapterRepo, story, chapter.ChapterNumber)
+
+	slog.InfoContext(ctx, "chapter restored", "chapter_id", id, "story_id", story.ID, "by_user", userID)
+	return nil
+}
+
+// PurgeStory permanently deletes a trashed story (admin only)
+func (s *TrashService) PurgeStory(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "TrashService.PurgeStory")
+	defer span.End()
+
+	story, err := s.trashRepo.GetStory(ctx, id)
+	if err != nil {
+		return err
+	}
+	if story == nil || story.DeletedAt == nil {
+		return apperror.NotFound("story_not_in_trash", "story not found in trash")
+	}
+
+	if err := s.trashRepo.PurgeStory(ctx, id); err != nil {
+		slog.ErrorContext(ctx, "failed to purge story", "error", err, "story_id", id)
+		return errors.New("failed to purge story")
+	}
+
+	slog.InfoContext(ctx, "story purged", "story_id", id, "slug", story.Slug)
+	return nil
+}
+
+// PurgeChapter permanently deletes a trashed chapter (admin only)
+func (s *TrashService) PurgeChapter(ctx context.Context, id int) error {
+	ctx, span := tracing.Start(ctx, "TrashService.PurgeChapter")
+	defer span.End()
+
+	chapter, err := s.trashRepo.GetDeletedChapter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if chapter == nil {
+		return apperror.NotFound("chapter_not_in_trash", "chapter not found in trash")
+	}
+
+	if err := s.trashRepo.PurgeChapter(ctx, id); err != nil {
+		slog.ErrorContext(ctx, "failed to purge chapter", "error", err, "chapter_id", id)
+		return errors.New("failed to purge chapter")
+	}
+
+	slog.InfoContext(ctx, "chapter purged", "chapter_id", id, "story_id", chapter.StoryID)
+	return nil
+}
+
+// PurgeExpired permanently deletes items that have outlived the retention
+// window. It runs as a background job.
+func (s *TrashService) PurgeExpired(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "TrashService.PurgeExpired")
+	defer span.End()
+
+	stories, chapters, err := s.trashRepo.PurgeExpired(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+	if stories > 0 || chapters > 0 {
+		slog.InfoContext(ctx, "expired trash purged", "stories", stories, "chapters", chapters)
+	}
+	return nil
+}
+
+func (s *TrashService) expired(deletedAt time.Time) bool {
+	return time.Since(deletedAt) > s.retention
+}
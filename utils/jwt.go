@@ -1,7 +1,18 @@
 package utils
 
 import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,43 +22,216 @@ type JWTClaims struct {
 	UserID   int    `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// SessionID ties the token to a row in user_sessions so it can be revoked
+	SessionID int `json:"sid,omitempty"`
+	// ImpersonatorID is the admin acting as this user; such tokens are read-only
+	ImpersonatorID int `json:"impersonator_id,omitempty"`
+	// Language is the user's preferred language for API messages
+	Language string `json:"lang,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// JWTConfig describes how tokens are signed and verified. When KeysDir is set,
+// every <kid>.pem private key in it (RSA or Ed25519, PKCS#8 or PKCS#1) is
+// loaded for verification and ActiveKeyID selects the one used for signing,
+// so keys can be rotated by adding a new file before retiring the old one.
+// Without KeysDir, tokens are signed with HS256 using Secret.
+//
+// HS256 tokens issued before issuer and audience were added carry neither;
+// they are accepted only until LegacyUntil, and never when it is zero.
+type JWTConfig struct {
+	Secret      string
+	KeysDir     string
+	ActiveKeyID string
+	Issuer      string
+	Audience    string
+	LegacyUntil time.Time
+	ExpiryHours int
+}
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type jwtKey struct {
+	id      string
+	method  jwt.SigningMethod
+	signKey interface{}
+	verify  interface{}
+}
+
 type JWTManager struct {
-	secretKey  []byte
-	expiryTime time.Duration
+	keys        map[string]*jwtKey
+	legacyKey   *jwtKey
+	activeKey   *jwtKey
+	issuer      string
+	audience    string
+	legacyUntil time.Time
+	expiryTime  time.Duration
 }
 
-func NewJWTManager(secret string, expiryHours int) *JWTManager {
-	return &JWTManager{
-		secretKey:  []byte(secret),
-		expiryTime: time.Duration(expiryHours) * time.Hour,
+func NewJWTManager(cfg JWTConfig) (*JWTManager, error) {
+	m := &JWTManager{
+		keys:        make(map[string]*jwtKey),
+		issuer:      cfg.Issuer,
+		audience:    cfg.Audience,
+		legacyUntil: cfg.LegacyUntil,
+		expiryTime:  time.Duration(cfg.ExpiryHours) * time.Hour,
+	}
+
+	// Tokens issued before asymmetric keys were configured carry no kid
+	if cfg.Secret != "" {
+		m.legacyKey = &jwtKey{
+			method:  jwt.SigningMethodHS256,
+			signKey: []byte(cfg.Secret),
+			verify:  []byte(cfg.Secret),
+		}
+	}
+
+	if cfg.KeysDir == "" {
+		if m.legacyKey == nil {
+			return nil, errors.New("jwt: either a secret or a keys directory is required")
+		}
+		m.activeKey = m.legacyKey
+		return m, nil
+	}
+
+	if err := m.loadKeys(cfg.KeysDir); err != nil {
+		return nil, err
+	}
+
+	activeID := cfg.ActiveKeyID
+	if activeID == "" && len(m.keys) == 1 {
+		for id := range m.keys {
+			activeID = id
+		}
 	}
+	active, ok := m.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("jwt: active key %q not found in %s", activeID, cfg.KeysDir)
+	}
+	m.activeKey = active
+
+	return m, nil
 }
 
-func (j *JWTManager) GenerateToken(userID int, username, role string) (string, error) {
-	claims := JWTClaims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.expiryTime)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+func (j *JWTManager) loadKeys(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return fmt.Errorf("jwt: failed to list keys: %w", err)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("jwt: no .pem keys found in %s", dir)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(j.secretKey)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("jwt: failed to read key %s: %w", path, err)
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+		key, err := parseSigningKey(kid, data)
+		if err != nil {
+			return fmt.Errorf("jwt: %s: %w", path, err)
+		}
+		j.keys[kid] = key
+	}
+
+	return nil
 }
 
-func (j *JWTManager) ValidateToken(tokenString string) (*JWTClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
+func parseSigningKey(kid string, data []byte) (*jwtKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM data")
+	}
+
+	var parsed interface{}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		rsaKey, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if rsaErr != nil {
+			return nil, fmt.Errorf("unsupported private key: %w", err)
 		}
-		return j.secretKey, nil
-	})
+		parsed = rsaKey
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &jwtKey{id: kid, method: jwt.SigningMethodRS256, signKey: key, verify: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &jwtKey{id: kid, method: jwt.SigningMethodEdDSA, signKey: key, verify: key.Public()}, nil
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+}
+
+// LegacyWindowOpen reports whether tokens from before issuer, audience and
+// sessions were introduced are still accepted
+func (j *JWTManager) LegacyWindowOpen() bool {
+	return time.Now().Before(j.legacyUntil)
+}
+
+// Expiry returns how long issued tokens stay valid
+func (j *JWTManager) Expiry() time.Duration {
+	return j.expiryTime
+}
+
+func (j *JWTManager) GenerateToken(userID int, username, role, language string, sessionID int) (string, error) {
+	return j.sign(JWTClaims{
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
+		Language:  language,
+	}, j.expiryTime)
+}
+
+// GenerateImpersonationToken issues a short-lived token for an admin acting as another user
+func (j *JWTManager) GenerateImpersonationToken(userID int, username, role string, sessionID, impersonatorID int, ttl time.Duration) (string, error) {
+	return j.sign(JWTClaims{
+		UserID:         userID,
+		Username:       username,
+		Role:           role,
+		SessionID:      sessionID,
+		ImpersonatorID: impersonatorID,
+	}, ttl)
+}
+
+func (j *JWTManager) sign(claims JWTClaims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    j.issuer,
+		Audience:  jwt.ClaimStrings{j.audience},
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+
+	token := jwt.NewWithClaims(j.activeKey.method, claims)
+	if j.activeKey.id != "" {
+		token.Header["kid"] = j.activeKey.id
+	}
+	return token.SignedString(j.activeKey.signKey)
+}
+
+func (j *JWTManager) ValidateToken(tokenString string) (*JWTClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, j.keyFunc,
+		jwt.WithValidMethods([]string{"HS256", "RS256", "EdDSA"}),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
 		return nil, err
@@ -58,5 +242,62 @@ func (j *JWTManager) ValidateToken(tokenString string) (*JWTClaims, error) {
 		return nil, errors.New("invalid token")
 	}
 
+	// HS256 tokens issued before issuer and audience were introduced carry
+	// neither claim; accept them with the legacy key while the configured
+	// transition window is open
+	_, hasKid := token.Header["kid"]
+	isLegacy := !hasKid && claims.Issuer == "" && len(claims.Audience) == 0
+	if !isLegacy || !j.LegacyWindowOpen() {
+		validator := jwt.NewValidator(jwt.WithIssuer(j.issuer), jwt.WithAudience(j.audience))
+		if err := validator.Validate(claims); err != nil {
+			return nil, err
+		}
+	}
+
 	return claims, nil
 }
+
+func (j *JWTManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	key := j.legacyKey
+	if kid, ok := token.Header["kid"].(string); ok {
+		key = j.keys[kid]
+	}
+	if key == nil {
+		return nil, errors.New("unknown signing key")
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.verify, nil
+}
+
+// JWKS returns the public halves of all asymmetric verification keys
+func (j *JWTManager) JWKS() JWKSet {
+	ids := make([]string, 0, len(j.keys))
+	for id := range j.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	set := JWKSet{Keys: make([]JWK, 0, len(ids))}
+	for _, id := range ids {
+		key := j.keys[id]
+		jwk := JWK{Kid: id, Use: "sig", Alg: key.method.Alg()}
+
+		switch pub := key.verify.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestJWTManager(t *testing.T) *JWTManager {
+	t.Helper()
+	return newTestJWTManagerUntil(t, time.Now().Add(time.Hour))
+}
+
+func newTestJWTManagerUntil(t *testing.T, legacyUntil time.Time) *JWTManager {
+	t.Helper()
+	m, err := NewJWTManager(JWTConfig{
+		Secret:      "test-secret",
+		Issuer:      "web-be",
+		Audience:    "web-fe",
+		LegacyUntil: legacyUntil,
+		ExpiryHours: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewJWTManager: %v", err)
+	}
+	return m
+}
+
+func TestValidateTokenAcceptsPreRotationToken(t *testing.T) {
+	m := newTestJWTManager(t)
+
+	// Shape of tokens issued before key rotation: HS256, no kid, iss or aud
+	claims := JWTClaims{
+		UserID:   7,
+		Username: "reader",
+		Role:     "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	got, err := m.ValidateToken(tokenString)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if got.UserID != 7 || got.Username != "reader" {
+		t.Fatalf("unexpected claims: %+v", got)
+	}
+}
+
+func TestValidateTokenRejectsPreRotationTokenOutsideWindow(t *testing.T) {
+	claims := JWTClaims{
+		UserID: 7,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	for name, until := range map[string]time.Time{
+		"not configured": {},
+		"closed":         time.Now().Add(-time.Minute),
+	} {
+		m := newTestJWTManagerUntil(t, until)
+		if _, err := m.ValidateToken(tokenString); err == nil {
+			t.Errorf("%s window: expected token without issuer and audience to be rejected", name)
+		}
+	}
+}
+
+func TestValidateTokenAcceptsIssuedToken(t *testing.T) {
+	m := newTestJWTManager(t)
+
+	tokenString, err := m.GenerateToken(7, "reader", "user", "en", 3)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	got, err := m.ValidateToken(tokenString)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if got.SessionID != 3 {
+		t.Fatalf("SessionID = %d, want 3", got.SessionID)
+	}
+}
+
+func TestValidateTokenRejectsWrongIssuer(t *testing.T) {
+	m := newTestJWTManager(t)
+
+	claims := JWTClaims{
+		UserID: 7,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "someone-else",
+			Audience:  jwt.ClaimStrings{"web-fe"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	if _, err := m.ValidateToken(tokenString); err == nil {
+		t.Fatal("expected token with a foreign issuer to be rejected")
+	}
+}
+
+func TestValidateTokenRejectsExpiredPreRotationToken(t *testing.T) {
+	m := newTestJWTManager(t)
+
+	claims := JWTClaims{
+		UserID: 7,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	if _, err := m.ValidateToken(tokenString); err == nil {
+		t.Fatal("expected expired token to be rejected")
+	}
+}
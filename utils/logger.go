@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 var Logger *slog.Logger
@@ -12,14 +16,81 @@ func InitLogger(env string) {
 
 	if env == "release" {
 		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelInfo,
+			Level:       slog.LevelInfo,
+			ReplaceAttr: redact,
 		})
 	} else {
 		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-			Level: slog.LevelDebug,
+			Level:       slog.LevelDebug,
+			ReplaceAttr: redact,
 		})
 	}
 
-	Logger = slog.New(handler)
+	Logger = slog.New(contextHandler{handler})
 	slog.SetDefault(Logger)
 }
+
+// redactedValue replaces the value of any sensitive attribute
+const redactedValue = "[REDACTED]"
+
+// sensitiveKeys are attribute keys whose values never reach the log, whatever
+// group they are logged under; keys ending in "password" are redacted too
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"secret":        true,
+	"client_secret": true,
+}
+
+func redact(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	if sensitiveKeys[key] || strings.HasSuffix(key, "password") {
+		return slog.String(a.Key, redactedValue)
+	}
+	return a
+}
+
+type logAttrsKey struct{}
+
+// WithLogAttrs returns a context whose log lines, written with slog.*Context,
+// carry attrs in addition to any the context already had. Middleware uses it
+// to stamp the request ID and user on everything logged for a request.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, logAttrsKey{}, merged)
+}
+
+// contextHandler adds the attributes set with WithLogAttrs and the IDs of the
+// span in a record's context, so lines logged with slog.*Context can be
+// matched to their request and trace
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(logAttrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -1,15 +1,48 @@
 package utils
 
-import "github.com/gin-gonic/gin"
+import (
+	"net/http"
+
+	"web-be/apperror"
+	"web-be/i18n"
+
+	"github.com/gin-gonic/gin"
+)
 
 type APIResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// Code and Details accompany Error so clients need not parse the message
+	Code    string                `json:"code,omitempty"`
+	Details []apperror.FieldError `json:"details,omitempty"`
+}
+
+// Language returns the language to answer in: the signed-in user's
+// preference, then the best match for Accept-Language, then i18n.Default
+func Language(c *gin.Context) string {
+	if lang := c.GetString("language"); i18n.Supported(lang) {
+		return lang
+	}
+	if lang := i18n.Match(c.GetHeader("Accept-Language")); lang != "" {
+		return lang
+	}
+	return i18n.Default
+}
+
+// localize picks the response language and labels the response with it
+func localize(c *gin.Context) string {
+	lang := Language(c)
+	c.Header("Content-Language", lang)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	return lang
 }
 
 func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
+	if message != "" {
+		message = i18n.T(localize(c), message)
+	}
 	c.JSON(statusCode, APIResponse{
 		Success: true,
 		Message: message,
@@ -19,8 +52,60 @@ func SuccessResponse(c *gin.Context, statusCode int, message string, data interf
 
 func ErrorResponse(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, APIResponse{
+		Success: false,
+		Error:   i18n.T(localize(c), message),
+	})
+}
+
+// MIMEProblemJSON is the media type of RFC 7807 problem details
+const MIMEProblemJSON = "application/problem+json"
+
+// ProblemDetails is an RFC 7807 error body. Code and Errors are extension
+// members carrying the same information as the default envelope.
+type ProblemDetails struct {
+	Type     string                `json:"type"`
+	Title    string                `json:"title"`
+	Status   int                   `json:"status"`
+	Detail   string                `json:"detail,omitempty"`
+	Instance string                `json:"instance,omitempty"`
+	Code     string                `json:"code,omitempty"`
+	Errors   []apperror.FieldError `json:"errors,omitempty"`
+}
+
+// AppErrorResponse writes err using the status and code of its kind. Clients
+// that prefer application/problem+json in Accept get problem details; everyone
+// else keeps the usual envelope.
+func AppErrorResponse(c *gin.Context, err *apperror.Error) {
+	status := err.Kind.Status()
+	lang := localize(c)
+	translate := func(key string, args ...any) string {
+		return i18n.T(lang, key, args...)
+	}
+	message := err.Text(translate)
+	var fields []apperror.FieldError
+	for _, f := range err.Fields {
+		fields = append(fields, apperror.FieldError{Field: f.Field, Message: translate(f.Message, f.Args...)})
+	}
+
+	if c.NegotiateFormat(gin.MIMEJSON, MIMEProblemJSON) == MIMEProblemJSON {
+		c.Header("Content-Type", MIMEProblemJSON)
+		c.JSON(status, ProblemDetails{
+			Type:     "about:blank",
+			Title:    translate(http.StatusText(status)),
+			Status:   status,
+			Detail:   message,
+			Instance: c.Request.URL.Path,
+			Code:     err.Code,
+			Errors:   fields,
+		})
+		return
+	}
+
+	c.JSON(status, APIResponse{
 		Success: false,
 		Error:   message,
+		Code:    err.Code,
+		Details: fields,
 	})
 }
 
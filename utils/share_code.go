@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+const shareCodeAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// ShareCodeLength is the length of codes used in shareable list URLs
+const ShareCodeLength = 12
+
+// GenerateShareCode returns a random base62 code for shareable URLs
+func GenerateShareCode() (string, error) {
+	buf := make([]byte, ShareCodeLength)
+	max := big.NewInt(int64(len(shareCodeAlphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = shareCodeAlphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
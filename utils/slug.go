@@ -45,6 +45,13 @@ func GenerateSlug(s string) string {
 	return s
 }
 
+// NormalizeTagName trims and lowercases a tag and collapses inner whitespace,
+// keeping diacritics so "Xuyên  Không" becomes "xuyên không"
+func NormalizeTagName(s string) string {
+	s = norm.NFC.String(s)
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
 // CountWords counts the number of words in a string
 func CountWords(s string) int {
 	fields := strings.Fields(s)
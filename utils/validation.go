@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+
+	"web-be/apperror"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// SetupValidator makes binding errors refer to fields by their JSON, form or
+// URI name instead of the Go struct field name
+func SetupValidator() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, key := range []string{"json", "form", "uri"} {
+			name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
+
+// BindingError converts an error from c.ShouldBind* into a validation error
+// listing every invalid field with a message derived from its validator tag
+func BindingError(err error) *apperror.Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]apperror.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			message, args := validationMessage(fe)
+			fields = append(fields, apperror.FieldError{Field: fieldPath(fe), Message: message, Args: args})
+		}
+		return apperror.Validation("validation_failed", "", fields...)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return apperror.Validation("validation_failed", "", apperror.FieldError{
+			Field: typeErr.Field, Message: "must be of type %s", Args: []any{typeErr.Type.String()},
+		})
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return apperror.Validation("malformed_body", "request body must be valid JSON")
+	}
+	if errors.Is(err, io.EOF) {
+		return apperror.Validation("malformed_body", "request body is required")
+	}
+	return apperror.Validation("invalid_request", "invalid request")
+}
+
+// fieldPath drops the request struct's name from the namespace, leaving a
+// path such as "settings.theme" or "story_ids[2]"
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return ns
+}
+
+// validationMessage returns the message format for a failed validator tag
+// along with its arguments
+func validationMessage(fe validator.FieldError) (string, []any) {
+	switch fe.Tag() {
+	case "required":
+		return "is required", nil
+	case "email":
+		return "must be a valid email address", nil
+	case "url":
+		return "must be a valid URL", nil
+	case "datetime":
+		return "must be a date in the format %s", []any{fe.Param()}
+	case "oneof":
+		return "must be one of: %s", []any{strings.Join(strings.Fields(fe.Param()), ", ")}
+	case "min", "max":
+		return boundMessages[fe.Tag()][kindGroup(fe.Kind())], []any{fe.Param()}
+	default:
+		return "is invalid", nil
+	}
+}
+
+// boundMessages holds the min and max messages for strings, collections and
+// numbers, in the order kindGroup numbers them
+var boundMessages = map[string][3]string{
+	"min": {"must be at least %s characters long", "must contain at least %s items", "must be at least %s"},
+	"max": {"must be at most %s characters long", "must contain at most %s items", "must be at most %s"},
+}
+
+func kindGroup(kind reflect.Kind) int {
+	switch kind {
+	case reflect.String:
+		return 0
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return 1
+	default:
+		return 2
+	}
+}